@@ -1,6 +1,13 @@
 package machine
 
+import (
+	"io"
+
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+)
+
 type IDeterminableMachineInfo interface {
 	IMachineInfo
-	Determine()
+	Determine() error
+	Explain(w io.Writer, format explain.Format) error
 }
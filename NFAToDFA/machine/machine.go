@@ -2,9 +2,10 @@ package machine
 
 import (
 	"bufio"
-	"encoding/csv"
 	"fmt"
-	"mooreMealyConversion/graph"
+	"github.com/AkshachRd/automata-theory-2023/NFAToDFA/graph"
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
 	"os"
 	"sort"
 )
@@ -24,44 +25,63 @@ type Machine struct {
 	InputSymbols []Symbol
 	States       map[State]bool
 	Transitions  Transitions[Transition]
+	Start        State
 }
 
 func NewMachine() *Machine {
-	return &Machine{make([]Symbol, 0), make(map[State]bool), make(Transitions[Transition])}
+	return &Machine{make([]Symbol, 0), make(map[State]bool), make(Transitions[Transition]), State{}}
 }
 
-func ReadMachineFromFile(filePath string) (*Machine, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return nil, err
+// NewMachineFromDFA builds the machine of a DFA, its start state included.
+func NewMachineFromDFA(dfa *automata.DFA) *Machine {
+	m := NewMachine()
+	states := make([]State, len(dfa.States))
+	for i, name := range dfa.States {
+		states[i] = State{Name: name, isFinal: dfa.Finals[i]}
+		m.States[states[i]] = true
 	}
-	defer file.Close()
+	m.Start = states[dfa.Start]
 
-	// Create a new CSV reader
-	reader := csv.NewReader(file)
+	for symbol, name := range dfa.Alphabet {
+		transition := make(Transition)
+		for state, row := range dfa.Transitions {
+			if target := row[symbol]; target != automata.NoState {
+				transition[states[state]] = states[target]
+			}
+		}
+		m.InputSymbols = append(m.InputSymbols, Symbol(name))
+		m.Transitions[Symbol(name)] = transition
+	}
 
-	// Read all the records
-	records, err := reader.ReadAll()
+	return m
+}
+
+// ReadMachineFromFile reads a DFA table, the output of the determinization.
+func ReadMachineFromFile(filePath string) (*Machine, error) {
+	t, err := table.ReadFile(filePath)
 	if err != nil {
-		fmt.Println("Error:", err)
 		return nil, err
 	}
-
-	// Print each record
-	for _, record := range records {
-		fmt.Println(record)
+	dfa, err := table.ParseDFA(t.Records)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	return NewMachineFromDFA(dfa), nil
 }
 
-func (m *Machine) Draw(outputFileName string) {
+func (m *Machine) Draw(outputFileName string) error {
 	graphView := graph.NewGraph()
 
 	nodes := make(map[State]int)
 	for state := range m.States {
 		nodes[state] = graphView.AddNode(state.Name)
+		if state.isFinal {
+			graphView.MarkFinal(nodes[state])
+		}
+	}
+	if start, ok := nodes[m.Start]; ok {
+		graphView.MarkStart(start)
 	}
 
 	type Edge struct {
@@ -74,7 +94,7 @@ func (m *Machine) Draw(outputFileName string) {
 	for inputSymbol, transition := range m.Transitions {
 		for state, transitionOutput := range transition {
 			first := nodes[state]
-			second := nodes[State{Name: transitionOutput.Name}]
+			second := nodes[transitionOutput]
 
 			edge := Edge{First: first, Second: second}
 			label := string(inputSymbol)
@@ -91,7 +111,7 @@ func (m *Machine) Draw(outputFileName string) {
 		graphView.AddEdge(edge.First, edge.Second, label)
 	}
 
-	graphView.GenerateImage(outputFileName)
+	return graphView.GenerateImage(outputFileName)
 }
 
 func (m *Machine) Print(outputFileName string) error {
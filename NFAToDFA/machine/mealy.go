@@ -3,7 +3,7 @@ package machine
 import (
 	"bufio"
 	"fmt"
-	"mooreMealyConversion/graph"
+	"github.com/AkshachRd/automata-theory-2023/NFAToDFA/graph"
 	"os"
 	"reflect"
 	"slices"
@@ -70,11 +70,14 @@ func (m *MealyMachine) ReadFromFile(scanner *bufio.Scanner, statesNum, inputSymb
 	return nil
 }
 
-func (m *MealyMachine) Draw(graph graph.IGraph) {
+func (m *MealyMachine) Draw(graph graph.IGraph) error {
 	nodes := make(map[MealyState]int)
 	for state := range m.States {
 		nodes[state] = graph.AddNode(state.Name)
 	}
+	if start, ok := nodes[m.CurrentState]; ok {
+		graph.MarkStart(start)
+	}
 
 	type Edge struct {
 		First  int
@@ -86,7 +89,10 @@ func (m *MealyMachine) Draw(graph graph.IGraph) {
 	for inputSymbol, transition := range m.Transitions {
 		for state, transitionOutput := range transition {
 			first := nodes[state]
-			second := nodes[MealyState{Name: transitionOutput.State.Name}]
+			second, ok := nodes[MealyState{Name: transitionOutput.State.Name}]
+			if !ok {
+				return fmt.Errorf("unknown state %s in transition from %s by %s", transitionOutput.State.Name, state.Name, inputSymbol)
+			}
 
 			edge := Edge{First: first, Second: second}
 			label := string(inputSymbol) + "/" + string(transitionOutput.OutputSymbol)
@@ -102,6 +108,8 @@ func (m *MealyMachine) Draw(graph graph.IGraph) {
 	for edge, label := range edges {
 		graph.AddEdge(edge.First, edge.Second, label)
 	}
+
+	return nil
 }
 
 func (m *MealyMachine) Print(file *os.File) error {
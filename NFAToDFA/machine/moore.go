@@ -3,7 +3,7 @@ package machine
 import (
 	"bufio"
 	"fmt"
-	"mooreMealyConversion/graph"
+	"github.com/AkshachRd/automata-theory-2023/NFAToDFA/graph"
 	"os"
 	"reflect"
 	"slices"
@@ -75,10 +75,13 @@ func (m *MooreMachine) ReadFromFile(scanner *bufio.Scanner, statesNum, inputSymb
 	return nil
 }
 
-func (m *MooreMachine) Draw(graph graph.IGraph) {
+func (m *MooreMachine) Draw(graph graph.IGraph) error {
 	nodes := make(map[MooreState]int)
 	for state := range m.States {
-		nodes[state] = graph.AddNode(state.Name + "/" + string(state.OutputSymbol))
+		nodes[state] = graph.AddOutputNode(state.Name, string(state.OutputSymbol))
+	}
+	if start, ok := nodes[m.CurrentState]; ok {
+		graph.MarkStart(start)
 	}
 
 	type Edge struct {
@@ -101,7 +104,7 @@ func (m *MooreMachine) Draw(graph graph.IGraph) {
 				}
 			}
 			if !found {
-				panic("invalid state")
+				return fmt.Errorf("unknown state %s in transition from %s by %s", transitionOutput, state.Name, inputSymbol)
 			}
 
 			to := nodes[MooreState{Name: transitionOutput, OutputSymbol: toNodeSymbol}]
@@ -120,6 +123,8 @@ func (m *MooreMachine) Draw(graph graph.IGraph) {
 	for edge, label := range edges {
 		graph.AddEdge(edge.From, edge.To, label)
 	}
+
+	return nil
 }
 
 func (m *MooreMachine) Print(file *os.File) error {
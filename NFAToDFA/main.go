@@ -1,58 +1,73 @@
 package main
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
 
 	"github.com/AkshachRd/automata-theory-2023/NFAToDFA/machine"
 	"github.com/AkshachRd/automata-theory-2023/NFAToDFA/moore"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
 )
 
 type Args struct {
 	SourceFilePath      string
 	DestinationFilePath string
+	ReportFilePath      string
 }
 
-func NewArgs(sourceFilePath, destinationFilePath string) (*Args, error) {
+func NewArgs(sourceFilePath, destinationFilePath, reportFilePath string) (*Args, error) {
 	return &Args{
 		SourceFilePath:      sourceFilePath,
 		DestinationFilePath: destinationFilePath,
+		ReportFilePath:      reportFilePath,
 	}, nil
 }
 
+// ParseArgs accepts <source> <destination> [report]. The report lists the
+// ε-closures and the NFA states behind every DFA state, as HTML for .html
+// files and Markdown otherwise.
 func ParseArgs(args []string) (*Args, error) {
-	if len(args) != 2 {
-		return nil, errors.New("incorrect arguments count")
+	switch len(args) {
+	case 2:
+		return NewArgs(args[0], args[1], "")
+	case 3:
+		return NewArgs(args[0], args[1], args[2])
 	}
 
-	return NewArgs(args[0], args[1])
+	return nil, errors.New("incorrect arguments count")
 }
 
-func getInfoFromFile(filePath string) ([]string, error) {
-    file, err := os.Open(filePath)
+func printDataToFile(data, filePath string) error {
+    return os.WriteFile(filePath, []byte(data), 0644)
+}
+
+func printReportToFile(machineInfo machine.IDeterminableMachineInfo, filePath string) error {
+    file, err := os.Create(filePath)
     if err != nil {
-        return nil, err
+        return err
     }
     defer file.Close()
 
-    var lines []string
-    scanner := bufio.NewScanner(file)
-    for scanner.Scan() {
-        lines = append(lines, scanner.Text())
-    }
-
-    return lines, scanner.Err()
+    return machineInfo.Explain(file, explain.FormatOf(filePath))
 }
 
-func printDataToFile(data, filePath string) error {
-    return os.WriteFile(filePath, []byte(data), 0644)
-}
-
-func processData(infoFromFile []string) (machine.IMachineInfo, error) {
-    machineInfo := moore.NewMooreMachineInfo(infoFromFile)
-    machineInfo.Determine()
+func processData(records [][]string, reportFilePath string) (machine.IMachineInfo, error) {
+    machineInfo, err := moore.NewMooreMachineInfo(records)
+    if err != nil {
+        return nil, err
+    }
+    err = machineInfo.Determine()
+    if err != nil {
+        return nil, err
+    }
+    if reportFilePath != "" {
+        err = printReportToFile(machineInfo, reportFilePath)
+        if err != nil {
+            return nil, err
+        }
+    }
 
     return machineInfo, nil
 }
@@ -64,19 +79,32 @@ func main() {
         return
     }
 
-    infoFromFile, err := getInfoFromFile(parsedArgs.SourceFilePath)
+    sourceTable, err := table.ReadFile(parsedArgs.SourceFilePath)
     if err != nil {
         fmt.Println(err)
         return
     }
 
-    machineInfo, err := processData(infoFromFile)
+    diagnostics := sourceTable.Validate(table.KindNFA)
+    diagnostics.Sort()
+    if len(diagnostics) > 0 {
+        fmt.Println(diagnostics)
+    }
+    if diagnostics.HasErrors() {
+        return
+    }
+
+    machineInfo, err := processData(sourceTable.Records, parsedArgs.ReportFilePath)
     if err != nil {
         fmt.Println(err)
         return
     }
 
-    csvData := machineInfo.GetCsvData()
+    csvData, err := machineInfo.GetCsvData()
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
 
     err = printDataToFile(csvData, parsedArgs.DestinationFilePath)
     if err != nil {
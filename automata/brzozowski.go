@@ -0,0 +1,22 @@
+package automata
+
+// MinimizeBrzozowski builds the minimal DFA of an NFA by reversing and
+// determinizing it twice, without a DFA of n itself along the way. The result
+// has no states from which no final state is reachable, so it is the machine
+// MinimizeDFA builds for a partial DFA. States are named q0, q1, … in
+// breadth-first order like in MinimizeDFA.
+func MinimizeBrzozowski(n *NFA) *DFA {
+	reversed := determinizeReversal(n)
+	dfa := determinizeReversal(reversed.ToNFA())
+
+	return renumbered(dfa)
+}
+
+// determinizeReversal is Determinize(Reverse(n)) without the start state
+// Reverse adds for several final states: the subset construction starts from
+// the final states themselves, so that the start subset does not differ from
+// the same states reached later only by that extra state.
+func determinizeReversal(n *NFA) *DFA {
+	reversed, finals := reverseTransitions(n)
+	return determinizeFrom(reversed, finals).DFA
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/diag"
+	"github.com/AkshachRd/automata-theory-2023/automata/document"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"github.com/AkshachRd/automata-theory-2023/automata/render"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+func runDeterminize(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("determinize", &flags)
+	explainPath := set.String("explain", "", "also write the ε-closures and the subset of every DFA state to this file, as HTML for .html and Markdown otherwise")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	nfa, err := table.ParseNFA(t.Records)
+	if err != nil {
+		return err
+	}
+
+	subsets := explain.Determinize(nfa)
+	if *explainPath != "" {
+		err = writeOutput(*explainPath, stdout, func(w io.Writer) error {
+			return explain.Write(w, subsets, explain.FormatOf(*explainPath))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeRecords(flags.out, stdout, table.DFARecords(subsets.DFA), t.Comma)
+}
+
+// How minimize treats missing transitions.
+const (
+	holesKeep     = "keep"
+	holesComplete = "complete"
+	holesDontCare = "dontcare"
+)
+
+func runMinimize(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("minimize", &flags)
+	kindName := set.String("type", "", "machine type: nfa, dfa, moore or mealy (default detected)")
+	algorithmName := set.String("algorithm", automata.Refinement.String(), "algorithm: refinement, hopcroft or brzozowski (DFA and NFA only)")
+	explainPath := set.String("explain", "", "also write the k-equivalence partitions to this file, as HTML for .html and Markdown otherwise")
+	holes := set.String("holes", holesKeep, "missing transitions: keep, complete (add a trap state first) or dontcare")
+	trap := set.String("trap", automata.TrapState, "name of the trap state of -holes complete")
+	trapOutput := set.String("trap-output", "", "output of the trap state of -holes complete for Moore and Mealy machines")
+	limit := set.Int("limit", automata.DefaultSearchLimit, "work the -holes dontcare reduction of a Mealy machine may spend proving its cover minimal, 0 for the greedy cover only")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	if *holes != holesKeep && *holes != holesComplete && *holes != holesDontCare {
+		return fmt.Errorf("unknown holes mode %q", *holes)
+	}
+
+	algorithm, err := automata.ParseAlgorithm(*algorithmName)
+	if err != nil {
+		return err
+	}
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	kind, err := parseKind(*kindName, t.Records)
+	if err != nil {
+		return err
+	}
+	if algorithm == automata.Brzozowski && kind != table.KindDFA && kind != table.KindNFA {
+		return errors.New("brzozowski minimization needs a DFA or NFA table")
+	}
+	if *holes != holesKeep && kind == table.KindNFA {
+		return errors.New("cannot fill the holes of an NFA, run determinize first")
+	}
+	if *holes == holesDontCare && algorithm != automata.Refinement {
+		return errors.New("-holes dontcare has no choice of algorithm")
+	}
+
+	var (
+		records     [][]string
+		explanation explain.Report
+	)
+	switch kind {
+	case table.KindDFA:
+		dfa, err := table.ParseDFA(t.Records)
+		if err != nil {
+			return err
+		}
+		switch *holes {
+		case holesComplete:
+			dfa = dfa.Complete(*trap)
+		case holesDontCare:
+			minimized := automata.MinimizeMooreDontCare(dfa.ToMoore(table.FinalOutput, ""))
+			records = table.DFARecords(minimized.ToDFA(table.FinalOutput))
+		}
+		if records == nil {
+			records = table.DFARecords(automata.MinimizeDFAWith(dfa, algorithm))
+			explanation = explain.DFA(dfa)
+		}
+	case table.KindMoore:
+		moore, err := table.ParseMoore(t.Records)
+		if err != nil {
+			return err
+		}
+		switch *holes {
+		case holesComplete:
+			moore = moore.Complete(*trap, *trapOutput)
+		case holesDontCare:
+			records = table.MooreRecords(automata.MinimizeMooreDontCare(moore))
+		}
+		if records == nil {
+			records = table.MooreRecords(automata.MinimizeMooreWith(moore, algorithm))
+			explanation = explain.Moore(moore)
+		}
+	case table.KindMealy:
+		mealy, err := table.ParseMealy(t.Records)
+		if err != nil {
+			return err
+		}
+		switch *holes {
+		case holesComplete:
+			mealy = mealy.Complete(*trap, *trapOutput)
+		case holesDontCare:
+			reduction := explain.Reduce(mealy, *limit)
+			records = table.MealyRecords(reduction.Result.Machine)
+			explanation = reduction
+		}
+		if records == nil {
+			records = table.MealyRecords(automata.MinimizeMealyWith(mealy, algorithm))
+			explanation = explain.Mealy(mealy)
+		}
+	default:
+		if algorithm != automata.Brzozowski {
+			return errors.New("cannot minimize an NFA, run determinize first or use the brzozowski algorithm")
+		}
+		nfa, err := table.ParseNFA(t.Records)
+		if err != nil {
+			return err
+		}
+		records = table.DFARecords(automata.MinimizeBrzozowski(nfa))
+	}
+
+	if *explainPath != "" {
+		if explanation == nil {
+			return errors.New("only the partitions of a DFA, Moore or Mealy machine and the reduction of a Mealy machine can be explained")
+		}
+		err = writeOutput(*explainPath, stdout, func(w io.Writer) error {
+			return explain.Write(w, explanation, explain.FormatOf(*explainPath))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeRecords(flags.out, stdout, records, t.Comma)
+}
+
+func runConvert(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("convert", &flags)
+	to := set.String("to", "", "target machine type: moore or mealy (default the other one)")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+
+	from := table.DetectKind(t.Records)
+	if from == table.KindNFA {
+		return errors.New("cannot convert an NFA, run determinize first")
+	}
+	if *to == "" {
+		*to = string(table.KindMealy)
+		if from == table.KindMealy {
+			*to = string(table.KindMoore)
+		}
+	}
+
+	var records [][]string
+	switch {
+	case table.Kind(*to) == table.KindMealy && from != table.KindMealy:
+		moore, err := table.ParseMoore(t.Records)
+		if err != nil {
+			return err
+		}
+		records = table.MealyRecords(moore.ToMealy())
+	case table.Kind(*to) == table.KindMoore && from == table.KindMealy:
+		mealy, err := table.ParseMealy(t.Records)
+		if err != nil {
+			return err
+		}
+		records = table.MooreRecords(mealy.ToMoore())
+	case table.Kind(*to) == table.KindMoore || table.Kind(*to) == table.KindMealy:
+		records = t.Records
+	default:
+		return fmt.Errorf("unknown target machine type %q", *to)
+	}
+
+	return writeRecords(flags.out, stdout, records, t.Comma)
+}
+
+func runDraw(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("draw", &flags)
+	kindName := set.String("type", "", "machine type: nfa, dfa, moore or mealy (default detected)")
+	formatName := set.String("format", string(render.FormatDOT), "output format: dot, svg, mermaid or plantuml")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := render.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	graph, err := readGraph(t, *kindName)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(flags.out, stdout, func(w io.Writer) error {
+		return render.Write(w, graph, format)
+	})
+}
+
+func readGraph(t *table.Table, kindName string) (*render.Graph, error) {
+	kind, err := parseKind(kindName, t.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case table.KindNFA:
+		nfa, err := table.ParseNFA(t.Records)
+		if err != nil {
+			return nil, err
+		}
+		return render.FromNFA(nfa), nil
+	case table.KindDFA:
+		dfa, err := table.ParseDFA(t.Records)
+		if err != nil {
+			return nil, err
+		}
+		return render.FromDFA(dfa), nil
+	case table.KindMoore:
+		moore, err := table.ParseMoore(t.Records)
+		if err != nil {
+			return nil, err
+		}
+		return render.FromMoore(moore), nil
+	default:
+		mealy, err := table.ParseMealy(t.Records)
+		if err != nil {
+			return nil, err
+		}
+		return render.FromMealy(mealy), nil
+	}
+}
+
+// splitWord reads a word as whitespace separated symbols. A single token that
+// is not a symbol itself but consists of one-character symbols is split into
+// characters, so "abba" works for the alphabet {a, b}.
+func splitWord(line string, alphabet automata.Alphabet) []string {
+	fields := strings.Fields(line)
+	if len(fields) != 1 || alphabet.Index(fields[0]) != -1 {
+		return fields
+	}
+
+	chars := make([]string, 0, utf8.RuneCountInString(fields[0]))
+	for _, char := range fields[0] {
+		if alphabet.Index(string(char)) == -1 {
+			return fields
+		}
+		chars = append(chars, string(char))
+	}
+
+	return chars
+}
+
+// errInvalid makes validate exit with status 1 once the diagnostics are
+// printed.
+var errInvalid = errors.New("machine is invalid")
+
+// runValidate prints every problem of the input as file:line:col: message
+// and fails when any of them is an error rather than a warning.
+func runValidate(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("validate", &flags)
+	from := set.String("from", "table", "input format: table or header (the mooreMealyConversion files)")
+	kindName := set.String("type", "", "machine type of a table: nfa, dfa, moore or mealy (default detected)")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	file := flags.in
+	if file == "" || file == "-" {
+		file = "<stdin>"
+	}
+	data, err := readInput(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+
+	var (
+		kind        string
+		diagnostics diag.List
+	)
+	switch *from {
+	case "table":
+		t, err := table.Read(bytes.NewReader(data))
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			diagnostics = diag.List{{Line: parseErr.Line, Column: parseErr.Column, Message: parseErr.Err.Error()}}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		tableKind, err := parseKind(*kindName, t.Records)
+		if err != nil {
+			return err
+		}
+		kind = string(tableKind)
+		diagnostics = t.Validate(tableKind)
+	case "header":
+		d, err := document.FromHeaderText(bytes.NewReader(data))
+		if !errors.As(err, &diagnostics) && err != nil {
+			return err
+		}
+		if d != nil {
+			kind = string(d.Type)
+		}
+	default:
+		return fmt.Errorf("unknown input format %q", *from)
+	}
+	diagnostics.SetFile(file)
+	diagnostics.Sort()
+
+	return writeOutput(flags.out, stdout, func(w io.Writer) error {
+		for _, d := range diagnostics {
+			fmt.Fprintln(w, d.Error())
+		}
+		if diagnostics.HasErrors() {
+			return errInvalid
+		}
+		_, err := fmt.Fprintf(w, "%s: ok\n", kind)
+		return err
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AkshachRd/automata-theory-2023/automata/document"
+	"github.com/AkshachRd/automata-theory-2023/automata/kiss"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+func runToDoc(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("todoc", &flags)
+	from := set.String("from", "table", "input format: table, kiss2 or header (the mooreMealyConversion files)")
+	kindName := set.String("type", "", "machine type of a table: nfa, dfa, moore or mealy (default detected)")
+	formatName := set.String("format", string(document.FormatJSON), "document format: json or yaml")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := document.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	data, err := readInput(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+
+	var d *document.Document
+	switch *from {
+	case "table":
+		t, err := table.Read(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		kind, err := parseKind(*kindName, t.Records)
+		if err != nil {
+			return err
+		}
+		d, err = document.FromRecords(t.Records, kind)
+		if err != nil {
+			return err
+		}
+	case "kiss2":
+		mealy, err := kiss.Read(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		d = document.FromMealy(mealy)
+	case "header":
+		d, err = document.FromHeaderText(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown input format %q", *from)
+	}
+
+	return writeOutput(flags.out, stdout, func(w io.Writer) error {
+		return document.Write(w, d, format)
+	})
+}
+
+func runFromDoc(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("fromdoc", &flags)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := readInput(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	d, err := document.Read(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	records, err := d.Records()
+	if err != nil {
+		return err
+	}
+
+	return writeRecords(flags.out, stdout, records, table.Semicolon)
+}
+
+func runSchema(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("schema", &flags)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	return writeOutput(flags.out, stdout, func(w io.Writer) error {
+		_, err := w.Write(document.Schema)
+		return err
+	})
+}
+
+func readInput(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(stdin)
+	}
+
+	return os.ReadFile(path)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+var errNotEquivalent = errors.New("machines are not equivalent")
+
+// runEquiv compares two machine tables. With a single file the second machine
+// is read from stdin, so a transformation can be checked in a pipeline:
+//
+//	automata minimize < m.csv | automata equiv m.csv
+func runEquiv(args []string, stdin io.Reader, stdout io.Writer) error {
+	set := flag.NewFlagSet("equiv", flag.ContinueOnError)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	if set.NArg() < 1 || set.NArg() > 2 {
+		return errors.New("expected one or two machine files")
+	}
+
+	left, err := readTable(set.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	right, err := readTable(set.Arg(1), stdin)
+	if err != nil {
+		return err
+	}
+
+	difference, err := compareTables(left.Records, right.Records)
+	if err != nil {
+		return err
+	}
+	if difference == nil {
+		fmt.Fprintln(stdout, "equivalent")
+		return nil
+	}
+
+	fmt.Fprintf(stdout, "not equivalent: %s\n", difference)
+	return errNotEquivalent
+}
+
+func compareTables(left, right [][]string) (*automata.Difference, error) {
+	leftKind, rightKind := table.DetectKind(left), table.DetectKind(right)
+	isAcceptor := func(kind table.Kind) bool {
+		return kind == table.KindNFA || kind == table.KindDFA
+	}
+
+	switch {
+	case isAcceptor(leftKind) && isAcceptor(rightKind):
+		leftDFA, err := readAcceptor(left)
+		if err != nil {
+			return nil, err
+		}
+		rightDFA, err := readAcceptor(right)
+		if err != nil {
+			return nil, err
+		}
+		return automata.Equivalent(leftDFA, rightDFA), nil
+	case leftKind == table.KindNFA || rightKind == table.KindNFA:
+		return nil, errors.New("cannot compare an NFA with a machine with outputs")
+	case leftKind == table.KindMealy || rightKind == table.KindMealy:
+		leftMealy, err := readAsMealy(left, leftKind)
+		if err != nil {
+			return nil, err
+		}
+		rightMealy, err := readAsMealy(right, rightKind)
+		if err != nil {
+			return nil, err
+		}
+		return automata.Equivalent(leftMealy, rightMealy), nil
+	default:
+		leftMoore, err := table.ParseMoore(left)
+		if err != nil {
+			return nil, err
+		}
+		rightMoore, err := table.ParseMoore(right)
+		if err != nil {
+			return nil, err
+		}
+		return automata.Equivalent(leftMoore, rightMoore), nil
+	}
+}
+
+// readAcceptor reads a DFA table as is and determinizes an NFA table.
+func readAcceptor(records [][]string) (*automata.DFA, error) {
+	nfa, err := table.ParseNFA(records)
+	if err != nil {
+		return nil, err
+	}
+	if dfa, err := nfa.ToDFA(); err == nil {
+		return dfa, nil
+	}
+
+	dfa, _ := automata.Determinize(nfa)
+	return dfa, nil
+}
+
+func readAsMealy(records [][]string, kind table.Kind) (*automata.Mealy, error) {
+	if kind == table.KindMealy {
+		return table.ParseMealy(records)
+	}
+
+	moore, err := table.ParseMoore(records)
+	if err != nil {
+		return nil, err
+	}
+	return moore.ToMealy(), nil
+}
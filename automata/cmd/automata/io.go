@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+type ioFlags struct {
+	in  string
+	out string
+}
+
+func newFlagSet(name string, flags *ioFlags) *flag.FlagSet {
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	set.StringVar(&flags.in, "in", "", "input file (default stdin)")
+	set.StringVar(&flags.out, "out", "", "output file (default stdout)")
+	return set
+}
+
+func readTable(path string, stdin io.Reader) (*table.Table, error) {
+	if path == "" || path == "-" {
+		return table.Read(stdin)
+	}
+
+	return table.ReadFile(path)
+}
+
+// writeOutput calls write with the output file, or with stdout when no file
+// was asked for.
+func writeOutput(path string, stdout io.Writer, write func(w io.Writer) error) error {
+	if path == "" || path == "-" {
+		return write(stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = write(file); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func writeRecords(path string, stdout io.Writer, records [][]string, comma rune) error {
+	return writeOutput(path, stdout, func(w io.Writer) error {
+		return table.Write(w, records, comma)
+	})
+}
+
+func parseKind(kind string, records [][]string) (table.Kind, error) {
+	if kind == "" {
+		return table.DetectKind(records), nil
+	}
+
+	switch table.Kind(kind) {
+	case table.KindDFA, table.KindNFA, table.KindMoore, table.KindMealy:
+		return table.Kind(kind), nil
+	}
+
+	return "", fmt.Errorf("unknown machine type %q", kind)
+}
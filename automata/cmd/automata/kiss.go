@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/kiss"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+func runFromKiss(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("fromkiss", &flags)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		mealy *automata.Mealy
+		err   error
+	)
+	if flags.in == "" || flags.in == "-" {
+		mealy, err = kiss.Read(stdin)
+	} else {
+		mealy, err = kiss.ReadFile(flags.in)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeRecords(flags.out, stdout, table.MealyRecords(mealy), table.Semicolon)
+}
+
+func runToKiss(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("tokiss", &flags)
+	kindName := set.String("type", "", "machine type: moore or mealy (default detected)")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	kind, err := parseKind(*kindName, t.Records)
+	if err != nil {
+		return err
+	}
+	if kind == table.KindNFA {
+		return errors.New("cannot write an NFA to KISS2, run determinize first")
+	}
+	mealy, err := readAsMealy(t.Records, kind)
+	if err != nil {
+		return err
+	}
+
+	if flags.out == "" || flags.out == "-" {
+		return kiss.Write(stdout, mealy)
+	}
+	return kiss.WriteFile(flags.out, mealy)
+}
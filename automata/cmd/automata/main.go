@@ -0,0 +1,96 @@
+// Command automata bundles the repository's machine tools behind one binary.
+// Every subcommand reads a machine table from stdin and writes its result to
+// stdout unless -in or -out is given, so steps can be piped together:
+//
+//	automata determinize < nfa.csv | automata minimize --type moore
+//	automata regex 'a(b|c)*' | automata determinize | automata minimize
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"regex", "build an NFA table with the e column from a regular expression", runRegex},
+	{"toregex", "derive a regular expression from a DFA or NFA by state elimination", runToRegex},
+	{"determinize", "build a DFA from an NFA table with the e column", runDeterminize},
+	{"minimize", "minimize a DFA, Moore or Mealy machine, optionally with holes as don't-cares", runMinimize},
+	{"complete", "add a trap state for the missing transitions of a machine", runComplete},
+	{"convert", "convert between Moore and Mealy machines", runConvert},
+	{"draw", "write the machine graph in DOT, SVG, Mermaid or PlantUML", runDraw},
+	{"simulate", "run input words through a machine", runSimulate},
+	{"fromkiss", "read a KISS2 benchmark into a Mealy table", runFromKiss},
+	{"tokiss", "write a Moore or Mealy table as KISS2", runToKiss},
+	{"todoc", "write a table, KISS2 or header file as a JSON or YAML document", runToDoc},
+	{"fromdoc", "read a JSON or YAML document into a machine table", runFromDoc},
+	{"schema", "print the JSON Schema of machine documents", runSchema},
+	{"validate", "check that a machine table is well formed", runValidate},
+	{"intersect", "accept the words both DFAs accept", productCommand(automata.Intersection)},
+	{"union", "accept the words either DFA accepts", productCommand(automata.Union)},
+	{"difference", "accept the words the first DFA accepts and the second does not", productCommand(automata.Subtraction)},
+	{"symdiff", "accept the words exactly one of two DFAs accepts", productCommand(automata.SymmetricDifference)},
+	{"complement", "accept the words a DFA rejects, completing it first", runComplement},
+	{"reverse", "accept the mirror images of the words of an NFA", runReverse},
+	{"concat", "accept a word of one NFA followed by a word of another", runConcat},
+	{"star", "accept any number of words of an NFA", runStar},
+	{"equiv", "check two machines for equivalence", runEquiv},
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: automata <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.name, c.description)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run 'automata <command> -h' for the flags of a command")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return
+	}
+
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+
+		err := c.run(os.Args[2:], os.Stdin, os.Stdout)
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if errors.Is(err, errNotEquivalent) || errors.Is(err, errInvalid) {
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "automata %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "automata: unknown command %q\n\n", name)
+	printUsage(os.Stderr)
+	os.Exit(2)
+}
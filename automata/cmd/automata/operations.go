@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+func runComplement(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("complement", &flags)
+	extra := set.String("alphabet", "", "comma separated input symbols to add to the alphabet of the machine")
+	minimize := set.Bool("minimize", false, "minimize the result")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	dfa, err := readAcceptorTable(t.Records)
+	if err != nil {
+		return err
+	}
+
+	var alphabet automata.Alphabet
+	if *extra != "" {
+		for _, symbol := range strings.Split(*extra, ",") {
+			alphabet = append(alphabet, strings.TrimSpace(symbol))
+		}
+	}
+	if alphabet.Index(table.EpsilonSymbol) != -1 {
+		return errors.New("input symbol e is the ε column of NFA tables")
+	}
+
+	complement := automata.Complement(dfa, alphabet)
+	if *minimize {
+		complement = automata.MinimizeDFA(complement)
+	}
+
+	return writeRecords(flags.out, stdout, table.DFARecords(complement), t.Comma)
+}
+
+func runComplete(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("complete", &flags)
+	kindName := set.String("type", "", "machine type: dfa, moore or mealy (default detected)")
+	trap := set.String("trap", automata.TrapState, "name of the trap state")
+	output := set.String("output", "", "output of the trap state of a Moore machine and of the added transitions of a Mealy machine")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	kind, err := parseKind(*kindName, t.Records)
+	if err != nil {
+		return err
+	}
+
+	var records [][]string
+	switch kind {
+	case table.KindDFA:
+		dfa, err := table.ParseDFA(t.Records)
+		if err != nil {
+			return err
+		}
+		records = table.DFARecords(dfa.Complete(*trap))
+	case table.KindMoore:
+		moore, err := table.ParseMoore(t.Records)
+		if err != nil {
+			return err
+		}
+		records = table.MooreRecords(moore.Complete(*trap, *output))
+	case table.KindMealy:
+		mealy, err := table.ParseMealy(t.Records)
+		if err != nil {
+			return err
+		}
+		records = table.MealyRecords(mealy.Complete(*trap, *output))
+	default:
+		return errors.New("cannot complete an NFA, run determinize first")
+	}
+
+	return writeRecords(flags.out, stdout, records, t.Comma)
+}
+
+func runReverse(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("reverse", &flags)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	nfa, err := readNFATable(t.Records)
+	if err != nil {
+		return err
+	}
+
+	return writeRecords(flags.out, stdout, table.NFARecords(automata.Reverse(nfa)), t.Comma)
+}
+
+// runConcat joins two machines like equiv does, with the second one read from
+// stdin when only one file is given.
+func runConcat(args []string, stdin io.Reader, stdout io.Writer) error {
+	set := flag.NewFlagSet("concat", flag.ContinueOnError)
+	out := set.String("out", "", "output file (default stdout)")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	if set.NArg() < 1 || set.NArg() > 2 {
+		return errors.New("expected one or two machine files")
+	}
+
+	left, err := readTable(set.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	right, err := readTable(set.Arg(1), stdin)
+	if err != nil {
+		return err
+	}
+	leftNFA, err := readNFATable(left.Records)
+	if err != nil {
+		return err
+	}
+	rightNFA, err := readNFATable(right.Records)
+	if err != nil {
+		return err
+	}
+
+	return writeRecords(*out, stdout, table.NFARecords(automata.Concatenate(leftNFA, rightNFA)), left.Comma)
+}
+
+func runStar(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("star", &flags)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	nfa, err := readNFATable(t.Records)
+	if err != nil {
+		return err
+	}
+
+	return writeRecords(flags.out, stdout, table.NFARecords(automata.Star(nfa)), t.Comma)
+}
+
+func readNFATable(records [][]string) (*automata.NFA, error) {
+	switch table.DetectKind(records) {
+	case table.KindNFA, table.KindDFA:
+		return table.ParseNFA(records)
+	}
+
+	return nil, errors.New("expected a DFA or NFA table")
+}
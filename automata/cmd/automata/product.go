@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+// productCommand runs the product construction of two DFA or NFA tables; an
+// NFA is determinized first. Like equiv, a single file is combined with the
+// machine on stdin:
+//
+//	automata regex 'a*b' | automata intersect -minimize words.csv
+func productCommand(operation automata.Operation) func(args []string, stdin io.Reader, stdout io.Writer) error {
+	return func(args []string, stdin io.Reader, stdout io.Writer) error {
+		set := flag.NewFlagSet(operation.String(), flag.ContinueOnError)
+		out := set.String("out", "", "output file (default stdout)")
+		minimize := set.Bool("minimize", false, "minimize the result")
+		if err := set.Parse(args); err != nil {
+			return err
+		}
+		if set.NArg() < 1 || set.NArg() > 2 {
+			return errors.New("expected one or two machine files")
+		}
+
+		left, err := readTable(set.Arg(0), stdin)
+		if err != nil {
+			return err
+		}
+		right, err := readTable(set.Arg(1), stdin)
+		if err != nil {
+			return err
+		}
+		leftDFA, err := readAcceptorTable(left.Records)
+		if err != nil {
+			return err
+		}
+		rightDFA, err := readAcceptorTable(right.Records)
+		if err != nil {
+			return err
+		}
+
+		dfa, _ := automata.Product(leftDFA, rightDFA, operation)
+		if *minimize {
+			dfa = automata.MinimizeDFA(dfa)
+		}
+
+		return writeRecords(*out, stdout, table.DFARecords(dfa), left.Comma)
+	}
+}
+
+func readAcceptorTable(records [][]string) (*automata.DFA, error) {
+	switch table.DetectKind(records) {
+	case table.KindNFA, table.KindDFA:
+		return readAcceptor(records)
+	}
+
+	return nil, errors.New("expected a DFA or NFA table")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata/regex"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+func runRegex(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("regex", &flags)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	var pattern string
+	switch {
+	case set.NArg() > 1:
+		return errors.New("expected one pattern")
+	case set.NArg() == 1:
+		pattern = set.Arg(0)
+	default:
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		pattern = strings.TrimSpace(string(data))
+	}
+
+	node, err := regex.Parse(pattern)
+	if err != nil {
+		return err
+	}
+	nfa := regex.Compile(node)
+	if nfa.Alphabet.Index(table.EpsilonSymbol) != -1 {
+		return fmt.Errorf("symbol %q is taken by the epsilon column, rename it", table.EpsilonSymbol)
+	}
+
+	return writeRecords(flags.out, stdout, table.NFARecords(nfa), table.Semicolon)
+}
+
+func runToRegex(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("toregex", &flags)
+	kindName := set.String("type", "", "machine type: dfa or nfa (default detected)")
+	orderName := set.String("order", regex.Weight.String(), "elimination order: natural, degree or weight")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	order, err := regex.ParseOrder(*orderName)
+	if err != nil {
+		return err
+	}
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	kind, err := parseKind(*kindName, t.Records)
+	if err != nil {
+		return err
+	}
+
+	var node *regex.Node
+	switch kind {
+	case table.KindDFA:
+		dfa, err := table.ParseDFA(t.Records)
+		if err != nil {
+			return err
+		}
+		node = regex.FromDFA(dfa, order)
+	case table.KindNFA:
+		nfa, err := table.ParseNFA(t.Records)
+		if err != nil {
+			return err
+		}
+		node = regex.FromNFA(nfa, order)
+	default:
+		return fmt.Errorf("cannot build a regular expression from a %s machine", kind)
+	}
+
+	return writeOutput(flags.out, stdout, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, regex.Simplify(node))
+		return err
+	})
+}
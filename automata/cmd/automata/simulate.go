@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+// runSimulate runs words given as arguments, in the -words file or, when the
+// machine comes from -in, on stdin line by line.
+func runSimulate(args []string, stdin io.Reader, stdout io.Writer) error {
+	var flags ioFlags
+	set := newFlagSet("simulate", &flags)
+	kindName := set.String("type", "", "machine type: dfa, moore or mealy (default detected)")
+	wordsPath := set.String("words", "", "file with one input word per line")
+	trace := set.Bool("trace", false, "print every transition taken")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	machineFromStdin := flags.in == "" || flags.in == "-"
+	t, err := readTable(flags.in, stdin)
+	if err != nil {
+		return err
+	}
+	kind, err := parseKind(*kindName, t.Records)
+	if err != nil {
+		return err
+	}
+
+	var simulate func(word []string) (*automata.Trace, error)
+	var alphabet automata.Alphabet
+	switch kind {
+	case table.KindDFA:
+		dfa, err := table.ParseDFA(t.Records)
+		if err != nil {
+			return err
+		}
+		alphabet, simulate = dfa.Alphabet, dfa.Simulate
+	case table.KindMoore:
+		moore, err := table.ParseMoore(t.Records)
+		if err != nil {
+			return err
+		}
+		alphabet, simulate = moore.Alphabet, moore.Simulate
+	case table.KindMealy:
+		mealy, err := table.ParseMealy(t.Records)
+		if err != nil {
+			return err
+		}
+		alphabet, simulate = mealy.Alphabet, mealy.Simulate
+	default:
+		return errors.New("cannot simulate an NFA, run determinize first")
+	}
+
+	return writeOutput(flags.out, stdout, func(w io.Writer) error {
+		run := func(line string) error {
+			result, err := simulate(splitWord(line, alphabet))
+			if *trace {
+				fmt.Fprintf(w, "word %q\n", line)
+				for _, step := range result.Steps {
+					fmt.Fprintf(w, "  %s --%s--> %s / %s\n", step.State, step.Input, step.Next, step.Output)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("word %q: %w", line, err)
+			}
+			switch kind {
+			case table.KindDFA:
+				verdict := result.Initial
+				if len(result.Outputs) > 0 {
+					verdict = result.Outputs[len(result.Outputs)-1]
+				}
+				_, err = fmt.Fprintln(w, verdict)
+			case table.KindMoore:
+				_, err = fmt.Fprintln(w, strings.Join(append([]string{result.Initial}, result.Outputs...), " "))
+			default:
+				_, err = fmt.Fprintln(w, strings.Join(result.Outputs, " "))
+			}
+			return err
+		}
+
+		for _, word := range set.Args() {
+			if err := run(word); err != nil {
+				return err
+			}
+		}
+
+		var words io.Reader
+		switch {
+		case *wordsPath != "":
+			file, err := os.Open(*wordsPath)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			words = file
+		case set.NArg() == 0 && !machineFromStdin:
+			words = stdin
+		default:
+			return nil
+		}
+
+		scanner := bufio.NewScanner(words)
+		for scanner.Scan() {
+			if err := run(scanner.Text()); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	})
+}
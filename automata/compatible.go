@@ -0,0 +1,469 @@
+package automata
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DontCare is the output of a Mealy transition that may output anything. In
+// outputs made of 0, 1 and - bits, like those of KISS2 benchmarks, every -
+// bit is a don't-care as well.
+const DontCare = "-"
+
+// DefaultSearchLimit is the work ReduceMealy is given by the tools to prove
+// a cover minimal before settling for the heuristic one.
+const DefaultSearchLimit = 100000
+
+// HasOutput reports whether the output of t is specified. A transition
+// without a target has an output only when one is given.
+func (t MealyTransition) HasOutput() bool {
+	return t.Output != DontCare && (t.Target != NoState || t.Output != "")
+}
+
+// IsSpecified reports whether m is complete and no output of it has
+// don't-cares, so that ReduceMealy gives what MinimizeMealy does.
+func (m *Mealy) IsSpecified() bool {
+	for _, row := range m.Transitions {
+		for _, transition := range row {
+			if transition.Target == NoState || !transition.HasOutput() || (isCube(transition.Output) && strings.Contains(transition.Output, "-")) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Reduction is a closed cover of an incompletely specified Mealy machine and
+// the machine it gives.
+type Reduction struct {
+	// Machine is the reduced machine; its state qi stands for the states
+	// Cover[i] of the original one.
+	Machine *Mealy
+	Cover   [][]int
+	// Compatibles are the maximal compatibles of the original machine.
+	Compatibles [][]int
+	// Exact reports whether no closed cover has fewer classes, that is the
+	// search finished within its limit or the heuristic cover was as small
+	// as a set of pairwise incompatible states.
+	Exact bool
+}
+
+// CompatiblePairs reports for every two states of m whether they are
+// compatible: no input word both of them specify gets different outputs from
+// them. It is the implication table of the states, where a pair is
+// incompatible when the outputs of an input conflict or when the pair leads
+// by an input to an incompatible pair.
+func CompatiblePairs(m *Mealy) [][]bool {
+	states := make([]int, len(m.States))
+	for state := range states {
+		states[state] = state
+	}
+
+	incompatible := incompatiblePairs(mealyTargets(m), states, func(p, q int) bool {
+		return outputsAgree(m, p, q)
+	})
+	for p := range incompatible {
+		for q := range incompatible[p] {
+			incompatible[p][q] = !incompatible[p][q]
+		}
+	}
+
+	return incompatible
+}
+
+// MaximalCompatibles returns the largest sets of pairwise compatible states
+// reachable in m, each sorted, in lexicographic order.
+func MaximalCompatibles(m *Mealy) [][]int {
+	return maximalCompatibles(CompatiblePairs(m), reachableList(m))
+}
+
+// ImpliedClasses returns the sets of states a set of compatible states leads
+// to by each input: a state standing for class needs a state standing for
+// each of them. Single states and sets within class are left out, as every
+// cover of the states satisfies them.
+func ImpliedClasses(m *Mealy, class []int) [][]int {
+	var implied [][]int
+	for symbol := range m.Alphabet {
+		var next []int
+		for _, state := range class {
+			if target := m.Transitions[state][symbol].Target; target != NoState && !slices.Contains(next, target) {
+				next = append(next, target)
+			}
+		}
+		slices.Sort(next)
+		if len(next) > 1 && !containsAll(class, next) && !slices.ContainsFunc(implied, func(other []int) bool {
+			return slices.Equal(other, next)
+		}) {
+			implied = append(implied, next)
+		}
+	}
+
+	return implied
+}
+
+// ReduceMealy finds a small closed cover of the reachable states of an
+// incompletely specified Mealy machine by compatible sets, where missing next
+// states and DontCare outputs may be anything. The reduced machine gives the
+// outputs of m on every input word m specifies them for.
+//
+// A cover found greedily is improved by a branch and bound search over the
+// prime compatibles. Finding the smallest cover is NP-hard, so limit bounds
+// the work of the search: the compatibles listed, the pairs of them compared
+// and the covers tried. Without a limit only the greedy cover is used.
+func ReduceMealy(m *Mealy, limit int) *Reduction {
+	compatible := CompatiblePairs(m)
+	states := reachableList(m)
+
+	search := coverSearch{
+		m:          m,
+		states:     states,
+		budget:     limit,
+		best:       greedyCover(m),
+		lowerBound: incompatibleBound(compatible, states),
+	}
+	reduction := &Reduction{Compatibles: maximalCompatibles(compatible, states)}
+	reduction.Exact = len(search.best) <= search.lowerBound
+	if !reduction.Exact && limit > 0 && search.primes(reduction.Compatibles) {
+		reduction.Exact = search.run(nil)
+	}
+
+	reduction.Machine, reduction.Cover = mealyFromCover(m, search.best)
+	return reduction
+}
+
+// outputsAgree reports whether p and q give outputs that merge by every
+// input both of them specify an output for.
+func outputsAgree(m *Mealy, p, q int) bool {
+	for symbol := range m.Alphabet {
+		left, right := m.Transitions[p][symbol], m.Transitions[q][symbol]
+		if !left.HasOutput() || !right.HasOutput() {
+			continue
+		}
+		if _, ok := mergeOutputs(left.Output, right.Output); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeOutputs returns the output that agrees with both a and b, and false
+// when there is none.
+func mergeOutputs(a, b string) (string, bool) {
+	switch {
+	case a == b || b == DontCare:
+		return a, true
+	case a == DontCare:
+		return b, true
+	case len(a) != len(b) || !isCube(a) || !isCube(b):
+		return "", false
+	}
+
+	merged := []byte(a)
+	for i := range merged {
+		switch {
+		case b[i] == '-':
+		case merged[i] == '-':
+			merged[i] = b[i]
+		case merged[i] != b[i]:
+			return "", false
+		}
+	}
+
+	return string(merged), true
+}
+
+func isCube(output string) bool {
+	return output != "" && strings.Trim(output, "01-") == ""
+}
+
+func reachableList(m *Mealy) []int {
+	var states []int
+	for state, reachable := range reachableStates(mealyTargets(m), m.Start) {
+		if reachable {
+			states = append(states, state)
+		}
+	}
+
+	return states
+}
+
+// maximalCompatibles lists the maximal cliques of the compatibility graph of
+// states with the Bron–Kerbosch algorithm.
+func maximalCompatibles(compatible [][]bool, states []int) [][]int {
+	var cliques [][]int
+	var extend func(clique, candidates, excluded []int)
+	extend = func(clique, candidates, excluded []int) {
+		if len(candidates) == 0 && len(excluded) == 0 {
+			cliques = append(cliques, slices.Clone(clique))
+			return
+		}
+
+		pivot := append(slices.Clone(candidates), excluded...)[0]
+		for _, state := range slices.Clone(candidates) {
+			if state != pivot && compatible[pivot][state] {
+				continue
+			}
+			neighbours := func(states []int) []int {
+				var result []int
+				for _, other := range states {
+					if other != state && compatible[state][other] {
+						result = append(result, other)
+					}
+				}
+				return result
+			}
+			extend(append(clique, state), neighbours(candidates), neighbours(excluded))
+			candidates = slices.DeleteFunc(candidates, func(other int) bool { return other == state })
+			excluded = append(excluded, state)
+		}
+	}
+	extend(nil, slices.Clone(states), nil)
+
+	for _, clique := range cliques {
+		slices.Sort(clique)
+	}
+	slices.SortFunc(cliques, slices.Compare[[]int])
+
+	return cliques
+}
+
+// greedyCover is the partition MinimizeMealyDontCare merges states by.
+func greedyCover(m *Mealy) [][]int {
+	classes, order, _ := mergeCompatible(mealyTargets(m), m.Start, func(p, q int) bool {
+		return outputsAgree(m, p, q)
+	})
+
+	cover := make([][]int, len(order))
+	for state, class := range classes {
+		if class != NoState {
+			cover[class] = append(cover[class], state)
+		}
+	}
+
+	return cover
+}
+
+// incompatibleBound returns the size of a set of pairwise incompatible
+// states, which no two classes of a cover can share.
+func incompatibleBound(compatible [][]bool, states []int) int {
+	var chosen []int
+	for _, state := range states {
+		if !slices.ContainsFunc(chosen, func(other int) bool { return compatible[state][other] }) {
+			chosen = append(chosen, state)
+		}
+	}
+
+	return len(chosen)
+}
+
+// coverSearch looks for a closed cover smaller than best among the prime
+// compatibles.
+type coverSearch struct {
+	m          *Mealy
+	states     []int
+	budget     int
+	best       [][]int
+	lowerBound int
+
+	candidates [][]int
+	implied    [][][]int
+}
+
+// spend takes one step of work from the budget and reports whether it was
+// left.
+func (s *coverSearch) spend() bool {
+	s.budget--
+	return s.budget >= 0
+}
+
+// primes lists every compatible, a subset of a maximal one, and keeps those
+// no larger compatible dominates, that is contains with only implied classes
+// within those of the smaller one. Some smallest closed cover consists of
+// prime compatibles. It reports false when the budget runs out.
+func (s *coverSearch) primes(maximal [][]int) bool {
+	seen := make(map[string]bool)
+	var compatibles [][]int
+	for _, class := range maximal {
+		if len(class) >= 62 || 1<<len(class) > s.budget {
+			return false
+		}
+		for subset := 1; subset < 1<<len(class); subset++ {
+			if !s.spend() {
+				return false
+			}
+			var compatible []int
+			for i, state := range class {
+				if subset&(1<<i) != 0 {
+					compatible = append(compatible, state)
+				}
+			}
+			if key := fmt.Sprint(compatible); !seen[key] {
+				seen[key] = true
+				compatibles = append(compatibles, compatible)
+			}
+		}
+	}
+	slices.SortStableFunc(compatibles, func(a, b []int) int { return len(b) - len(a) })
+
+	implied := make([][][]int, len(compatibles))
+	for i, compatible := range compatibles {
+		implied[i] = ImpliedClasses(s.m, compatible)
+	}
+	for i, compatible := range compatibles {
+		prime := true
+		for j := 0; j < i && prime; j++ {
+			if !s.spend() {
+				return false
+			}
+			if len(compatibles[j]) > len(compatible) && containsAll(compatibles[j], compatible) && impliesWithin(implied[j], implied[i]) {
+				prime = false
+			}
+		}
+		if prime {
+			s.candidates = append(s.candidates, compatible)
+			s.implied = append(s.implied, implied[i])
+		}
+	}
+
+	return true
+}
+
+// run extends the candidates selected so far until they make a closed cover,
+// trying every candidate that satisfies the first unmet requirement. It
+// reports false when the budget runs out.
+func (s *coverSearch) run(selected []int) bool {
+	if !s.spend() {
+		return false
+	}
+
+	requirement := s.requirement(selected)
+	if requirement == nil {
+		s.best = make([][]int, 0, len(selected))
+		for _, candidate := range selected {
+			s.best = append(s.best, s.candidates[candidate])
+		}
+		return true
+	}
+	if len(selected)+1 >= len(s.best) {
+		return true
+	}
+
+	for candidate, class := range s.candidates {
+		if !containsAll(class, requirement) {
+			continue
+		}
+		if !s.run(append(selected, candidate)) {
+			return false
+		}
+		if len(s.best) <= s.lowerBound {
+			return true
+		}
+	}
+
+	return true
+}
+
+// requirement returns a reachable state no selected candidate covers, or else
+// an implied class of a selected candidate no selected candidate contains,
+// and nil for a closed cover.
+func (s *coverSearch) requirement(selected []int) []int {
+	within := func(states []int) bool {
+		return slices.ContainsFunc(selected, func(candidate int) bool {
+			return containsAll(s.candidates[candidate], states)
+		})
+	}
+
+	for _, state := range s.states {
+		if !within([]int{state}) {
+			return []int{state}
+		}
+	}
+	for _, candidate := range selected {
+		for _, class := range s.implied[candidate] {
+			if !within(class) {
+				return class
+			}
+		}
+	}
+
+	return nil
+}
+
+// impliesWithin reports whether every class of larger is within a class of
+// smaller.
+func impliesWithin(larger, smaller [][]int) bool {
+	for _, class := range larger {
+		if !slices.ContainsFunc(smaller, func(other []int) bool { return containsAll(other, class) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsAll(set, subset []int) bool {
+	for _, state := range subset {
+		if !slices.Contains(set, state) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mealyFromCover builds the machine of a closed cover. A class leads by an
+// input to the first class containing all next states of its states, and
+// outputs what they output merged. The classes reachable from one containing
+// the start state are numbered in breadth-first order and returned in that
+// order.
+func mealyFromCover(m *Mealy, cover [][]int) (*Mealy, [][]int) {
+	targets := newTransitionTable(len(cover), len(m.Alphabet))
+	outputs := make([][]string, len(cover))
+	for class, states := range cover {
+		outputs[class] = make([]string, len(m.Alphabet))
+		for symbol := range m.Alphabet {
+			var next []int
+			output, specified := DontCare, false
+			for _, state := range states {
+				transition := m.Transitions[state][symbol]
+				if transition.Target != NoState {
+					next = append(next, transition.Target)
+				}
+				if transition.HasOutput() {
+					output, _ = mergeOutputs(output, transition.Output)
+					specified = true
+				}
+			}
+
+			if len(next) > 0 {
+				targets[class][symbol] = slices.IndexFunc(cover, func(other []int) bool { return containsAll(other, next) })
+			} else if !specified {
+				output = ""
+			}
+			outputs[class][symbol] = output
+		}
+	}
+
+	identity := make([]int, len(cover))
+	for class := range identity {
+		identity[class] = class
+	}
+	start := slices.IndexFunc(cover, func(class []int) bool { return slices.Contains(class, m.Start) })
+	order, next := quotient(targets, start, identity)
+
+	reduced := NewMealy(quotientNames(len(order)), m.Alphabet)
+	ordered := make([][]int, len(order))
+	for number, class := range order {
+		ordered[number] = cover[class]
+		for symbol, target := range next[number] {
+			if target != NoState || outputs[class][symbol] != "" {
+				reduced.Transitions[number][symbol] = MealyTransition{Target: target, Output: outputs[class][symbol]}
+			}
+		}
+	}
+
+	return reduced, ordered
+}
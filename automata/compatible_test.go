@@ -0,0 +1,191 @@
+package automata
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// dontCareMealy is the textbook machine with s0 and s2 incompatible by x0 and
+// s1 compatible with both, so that its smallest closed cover has two classes.
+func dontCareMealy() *Mealy {
+	m := NewMealy([]string{"s0", "s1", "s2"}, Alphabet{"x0", "x1"})
+	m.Transitions[0] = []MealyTransition{{Target: 1, Output: "0"}, {Target: 2, Output: DontCare}}
+	m.Transitions[1] = []MealyTransition{{Target: NoState}, {Target: 2, Output: "1"}}
+	m.Transitions[2] = []MealyTransition{{Target: 0, Output: "1"}, {Target: NoState, Output: "1"}}
+	return m
+}
+
+// randomDontCareMealy is randomMealy with outputs 0, 1 and DontCare and about
+// a quarter of the next states missing.
+func randomDontCareMealy(rng *rand.Rand, statesNum, symbolsNum int) *Mealy {
+	m := randomMealy(rng, statesNum, symbolsNum, 2)
+	for _, row := range m.Transitions {
+		for symbol := range row {
+			row[symbol].Output = []string{"0", "1", DontCare}[rng.Intn(3)]
+			if rng.Intn(4) == 0 {
+				row[symbol].Target = NoState
+			}
+		}
+	}
+
+	return m
+}
+
+// smallestClosedCover counts the classes of a smallest closed cover of the
+// reachable states of m by trying every set of compatibles.
+func smallestClosedCover(m *Mealy) int {
+	compatible := CompatiblePairs(m)
+	states := reachableList(m)
+
+	var classes [][]int
+	for subset := 1; subset < 1<<len(states); subset++ {
+		var class []int
+		for i, state := range states {
+			if subset&(1<<i) != 0 {
+				class = append(class, state)
+			}
+		}
+		if !slices.ContainsFunc(class, func(p int) bool {
+			return slices.ContainsFunc(class, func(q int) bool { return !compatible[p][q] })
+		}) {
+			classes = append(classes, class)
+		}
+	}
+
+	closed := func(cover [][]int) bool {
+		within := func(states []int) bool {
+			return slices.ContainsFunc(cover, func(class []int) bool { return containsAll(class, states) })
+		}
+		for _, state := range states {
+			if !within([]int{state}) {
+				return false
+			}
+		}
+		for _, class := range cover {
+			for _, implied := range ImpliedClasses(m, class) {
+				if !within(implied) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for size := 1; ; size++ {
+		found := false
+		var choose func(from int, cover [][]int)
+		choose = func(from int, cover [][]int) {
+			if found || len(cover) == size {
+				found = found || closed(cover)
+				return
+			}
+			for i := from; i < len(classes); i++ {
+				choose(i+1, append(cover, classes[i]))
+			}
+		}
+		choose(0, nil)
+		if found {
+			return size
+		}
+	}
+}
+
+// checkCovers fails unless reduced gives the output m specifies on every
+// word of up to depth inputs.
+func checkCovers(t *testing.T, m, reduced *Mealy, depth int) {
+	t.Helper()
+	var walk func(state, reducedState, depth int)
+	walk = func(state, reducedState, depth int) {
+		if depth == 0 {
+			return
+		}
+		for symbol := range m.Alphabet {
+			transition, reducedTransition := m.Transitions[state][symbol], reduced.Transitions[reducedState][symbol]
+			if transition.HasOutput() && transition.Output != reducedTransition.Output {
+				t.Fatalf("%s by %s outputs %s, the reduced machine %s", m.States[state], m.Alphabet[symbol], transition.Output, reducedTransition.Output)
+			}
+			if transition.Target == NoState {
+				continue
+			}
+			if reducedTransition.Target == NoState {
+				t.Fatalf("%s by %s has a next state, the reduced machine has none", m.States[state], m.Alphabet[symbol])
+			}
+			walk(transition.Target, reducedTransition.Target, depth-1)
+		}
+	}
+	walk(m.Start, reduced.Start, depth)
+}
+
+func TestCompatibles(t *testing.T) {
+	m := dontCareMealy()
+
+	compatible := CompatiblePairs(m)
+	if !compatible[0][1] || !compatible[1][2] || compatible[0][2] || compatible[2][0] {
+		t.Errorf("compatible pairs %v", compatible)
+	}
+	if got, want := MaximalCompatibles(m), [][]int{{0, 1}, {1, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("maximal compatibles %v, want %v", got, want)
+	}
+	if got := ImpliedClasses(m, []int{0, 1}); len(got) != 0 {
+		t.Errorf("{s0, s1} implies %v, want nothing", got)
+	}
+	if got, want := ImpliedClasses(m, []int{0, 2}), [][]int{{0, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("{s0, s2} implies %v, want %v", got, want)
+	}
+}
+
+func TestReduceMealy(t *testing.T) {
+	m := dontCareMealy()
+
+	for _, limit := range []int{0, DefaultSearchLimit} {
+		reduction := ReduceMealy(m, limit)
+		if len(reduction.Cover) != 2 || !reduction.Exact {
+			t.Errorf("limit %d: cover %v, exact %v", limit, reduction.Cover, reduction.Exact)
+		}
+		checkCovers(t, m, reduction.Machine, 6)
+	}
+}
+
+func TestReduceMealySpecifiedIsMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		m := randomMealy(rng, 1+rng.Intn(8), 1+rng.Intn(3), 2)
+		reduction := ReduceMealy(m, DefaultSearchLimit)
+		if !reduction.Exact || !reflect.DeepEqual(reduction.Machine, MinimizeMealy(m)) {
+			t.Fatalf("machine %d: reduced to %+v, minimized to %+v", i, reduction.Machine, MinimizeMealy(m))
+		}
+	}
+}
+
+func TestReduceMealyFindsSmallestCover(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	improved := 0
+	for i := 0; i < 300; i++ {
+		m := randomDontCareMealy(rng, 2+rng.Intn(5), 1+rng.Intn(2))
+		smallest := smallestClosedCover(m)
+
+		greedy := ReduceMealy(m, 0)
+		if len(greedy.Cover) < smallest || (greedy.Exact && len(greedy.Cover) != smallest) {
+			t.Fatalf("machine %d: greedy cover %v, exact %v, smallest has %d classes", i, greedy.Cover, greedy.Exact, smallest)
+		}
+		checkCovers(t, m, greedy.Machine, 6)
+
+		reduction := ReduceMealy(m, DefaultSearchLimit)
+		if !reduction.Exact || len(reduction.Cover) != smallest {
+			t.Fatalf("machine %d: cover %v, exact %v, smallest has %d classes", i, reduction.Cover, reduction.Exact, smallest)
+		}
+		checkCovers(t, m, reduction.Machine, 6)
+
+		if len(greedy.Cover) > smallest {
+			improved++
+			if bounded := ReduceMealy(m, 1); bounded.Exact || len(bounded.Cover) != len(greedy.Cover) {
+				t.Errorf("machine %d: with limit 1 cover %v, exact %v, want the greedy one", i, bounded.Cover, bounded.Exact)
+			}
+		}
+	}
+	if improved == 0 {
+		t.Errorf("no greedy cover was improved, the search went untested")
+	}
+}
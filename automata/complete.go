@@ -0,0 +1,81 @@
+package automata
+
+// TrapState is the default name of the state Complete adds.
+const TrapState = "trap"
+
+// Complete returns a copy of d in which every missing transition leads to a
+// new non-final trap state that loops on every symbol. The trap is named trap,
+// followed by a number when a state has that name already, and is only added
+// when d is incomplete.
+func (d *DFA) Complete(trap string) *DFA {
+	completed := d.Clone()
+	if completed.IsComplete() {
+		return completed
+	}
+
+	state := len(completed.States)
+	completed.States = append(completed.States, uniqueName(completed.States, trap))
+	completed.Finals = append(completed.Finals, false)
+	completed.Transitions = append(completed.Transitions, newTransitionRow(len(completed.Alphabet)))
+	fillHoles(completed.Transitions, state)
+
+	return completed
+}
+
+// Complete is DFA.Complete for Moore machines; the trap state has the given
+// output.
+func (m *Moore) Complete(trap, output string) *Moore {
+	completed := m.Clone()
+	if completed.IsComplete() {
+		return completed
+	}
+
+	state := len(completed.States)
+	completed.States = append(completed.States, uniqueName(completed.States, trap))
+	completed.Outputs = append(completed.Outputs, output)
+	completed.Transitions = append(completed.Transitions, newTransitionRow(len(completed.Alphabet)))
+	fillHoles(completed.Transitions, state)
+
+	return completed
+}
+
+// Complete is DFA.Complete for Mealy machines; every transition added, the
+// loops of the trap state included, has the given output. A -/y transition
+// keeps its output y and only gets the trap state as its target.
+func (m *Mealy) Complete(trap, output string) *Mealy {
+	completed := m.Clone()
+	if completed.IsComplete() {
+		return completed
+	}
+
+	state := len(completed.States)
+	completed.States = append(completed.States, uniqueName(completed.States, trap))
+	trapRow := make([]MealyTransition, len(completed.Alphabet))
+	for symbol := range trapRow {
+		trapRow[symbol] = MealyTransition{Target: NoState}
+	}
+	completed.Transitions = append(completed.Transitions, trapRow)
+	for _, row := range completed.Transitions {
+		for symbol, transition := range row {
+			if transition.Target != NoState {
+				continue
+			}
+			row[symbol].Target = state
+			if !transition.HasOutput() {
+				row[symbol].Output = output
+			}
+		}
+	}
+
+	return completed
+}
+
+func fillHoles(transitions [][]int, trap int) {
+	for _, row := range transitions {
+		for symbol, target := range row {
+			if target == NoState {
+				row[symbol] = trap
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package automata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMealyCompleteKeepsOutputs(t *testing.T) {
+	// s0 has x;-/1 and no transition by z.
+	m := NewMealy([]string{"s0"}, Alphabet{"x", "z"})
+	m.Transitions[0][0] = MealyTransition{Target: NoState, Output: "1"}
+
+	completed := m.Complete(TrapState, "0")
+	if !completed.IsComplete() || len(completed.States) != 2 {
+		t.Fatalf("completed %+v", completed)
+	}
+	want := [][]MealyTransition{
+		{{Target: 1, Output: "1"}, {Target: 1, Output: "0"}},
+		{{Target: 1, Output: "0"}, {Target: 1, Output: "0"}},
+	}
+	if !reflect.DeepEqual(completed.Transitions, want) {
+		t.Errorf("transitions %v, want %v", completed.Transitions, want)
+	}
+
+	trace, err := completed.Simulate([]string{"x", "z"})
+	if err != nil || !reflect.DeepEqual(trace.Outputs, []string{"1", "0"}) {
+		t.Errorf("word x z: outputs %v, %v", trace.Outputs, err)
+	}
+	if m.Transitions[0][0].Target != NoState {
+		t.Errorf("Complete changed the original machine")
+	}
+}
@@ -0,0 +1,152 @@
+package automata
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Determinize runs the subset construction over the ε-closed subsets reachable
+// from the start state. State i of the result is named Si and stands for the
+// NFA states listed in subsets[i]; subsets are sorted, so two subsets are the
+// same DFA state exactly when they hold the same state indexes.
+func Determinize(nfa *NFA) (dfa *DFA, subsets [][]int) {
+	d := DeterminizeSteps(nfa)
+	return d.DFA, d.Subsets
+}
+
+// Determinization is what the subset construction computes along the way. DFA
+// states are numbered in the order the worklist discovers them, so Si is the
+// i-th state taken from it.
+type Determinization struct {
+	DFA *DFA
+	// Closures[state] is the ε-closure of an NFA state; the closure of a set
+	// of states is the union of theirs.
+	Closures [][]int
+	Subsets  [][]int
+	// Moves[i][symbol] holds the sorted NFA states reached from Subsets[i] by
+	// a symbol, before their ε-closure becomes a DFA state.
+	Moves [][][]int
+	// Sources[i] is the transition that discovered Si; the start state has
+	// none and NoState in both fields.
+	Sources []Source
+}
+
+type Source struct {
+	From   int
+	Symbol int
+}
+
+// DeterminizeSteps is Determinize keeping the closures, moves and order of
+// discovery of the construction.
+func DeterminizeSteps(nfa *NFA) *Determinization {
+	return determinizeFrom(nfa, []int{nfa.Start})
+}
+
+// determinizeFrom is DeterminizeSteps starting with the ε-closure of a set of
+// states rather than of the start state alone.
+func determinizeFrom(nfa *NFA, starts []int) *Determinization {
+	d := &Determinization{
+		DFA:      &DFA{Alphabet: append(Alphabet{}, nfa.Alphabet...)},
+		Closures: make([][]int, len(nfa.States)),
+	}
+	for state := range nfa.States {
+		d.Closures[state] = nfa.EpsilonClosure([]int{state})
+	}
+	indexes := make(map[string]int)
+	inClosure := make([]bool, len(nfa.States))
+
+	closure := func(states []int) []int {
+		var union []int
+		for _, state := range states {
+			for _, reached := range d.Closures[state] {
+				if !inClosure[reached] {
+					inClosure[reached] = true
+					union = append(union, reached)
+				}
+			}
+		}
+		for _, state := range union {
+			inClosure[state] = false
+		}
+		slices.Sort(union)
+		return union
+	}
+
+	addSubset := func(subset []int, source Source) int {
+		key := subsetKey(subset)
+		if index, ok := indexes[key]; ok {
+			return index
+		}
+
+		index := len(d.Subsets)
+		indexes[key] = index
+		d.Subsets = append(d.Subsets, subset)
+		d.Sources = append(d.Sources, source)
+
+		final := false
+		for _, state := range subset {
+			final = final || nfa.Finals[state]
+		}
+		d.DFA.States = append(d.DFA.States, "S"+strconv.Itoa(index))
+		d.DFA.Finals = append(d.DFA.Finals, final)
+		d.DFA.Transitions = append(d.DFA.Transitions, newTransitionRow(len(nfa.Alphabet)))
+
+		return index
+	}
+
+	d.DFA.Start = addSubset(closure(starts), Source{From: NoState, Symbol: NoState})
+	for current := 0; current < len(d.Subsets); current++ {
+		moves := make([][]int, len(nfa.Alphabet))
+		for symbol := range nfa.Alphabet {
+			moved := nfa.Move(d.Subsets[current], symbol)
+			slices.Sort(moved)
+			moves[symbol] = moved
+			if len(moved) == 0 {
+				continue
+			}
+			d.DFA.Transitions[current][symbol] = addSubset(closure(moved), Source{From: current, Symbol: symbol})
+		}
+		d.Moves = append(d.Moves, moves)
+	}
+
+	return d
+}
+
+// Move returns the states reachable from states by a single symbol transition.
+func (n *NFA) Move(states []int, symbol int) []int {
+	seen := make(map[int]struct{})
+	moved := make([]int, 0)
+	for _, state := range states {
+		for _, target := range n.Transitions[state][symbol] {
+			if _, ok := seen[target]; !ok {
+				seen[target] = struct{}{}
+				moved = append(moved, target)
+			}
+		}
+	}
+
+	return moved
+}
+
+// SubsetName renders a subset of NFA states as {S1,S12}.
+func (n *NFA) SubsetName(subset []int) string {
+	names := make([]string, len(subset))
+	for i, state := range subset {
+		names[i] = n.States[state]
+	}
+
+	return "{" + strings.Join(names, ",") + "}"
+}
+
+func subsetKey(subset []int) string {
+	var key strings.Builder
+	for i, state := range subset {
+		if i != 0 {
+			key.WriteByte(',')
+		}
+		key.WriteString(strconv.Itoa(state))
+	}
+
+	return key.String()
+}
@@ -0,0 +1,66 @@
+package automata
+
+import "testing"
+
+func TestDeterminizeSubsetsWithSimilarNames(t *testing.T) {
+	// {S1,S12} and {S11,S2} are the same characters once the commas are gone.
+	nfa := NewNFA(namedStates(13), Alphabet{"a", "b"})
+	nfa.AddTransition(0, 0, 1)
+	nfa.AddTransition(0, 0, 12)
+	nfa.AddTransition(0, 1, 11)
+	nfa.AddTransition(0, 1, 2)
+	nfa.Finals[12] = true
+
+	dfa, subsets := Determinize(nfa)
+	if len(dfa.States) != 3 {
+		t.Fatalf("DFA has %d states, want 3", len(dfa.States))
+	}
+	if name := nfa.SubsetName(subsets[dfa.Next(dfa.Start, 0)]); name != "{S1,S12}" {
+		t.Errorf("a leads to %s, want {S1,S12}", name)
+	}
+	if name := nfa.SubsetName(subsets[dfa.Next(dfa.Start, 1)]); name != "{S2,S11}" {
+		t.Errorf("b leads to %s, want {S2,S11}", name)
+	}
+	for word, want := range map[string]bool{"a": true, "b": false} {
+		if accepted, err := dfa.Accepts([]string{word}); err != nil || accepted != want {
+			t.Errorf("word %s: accepted %v, %v, want %v", word, accepted, err, want)
+		}
+	}
+}
+
+func TestDeterminizeManyStates(t *testing.T) {
+	// The words whose 12th symbol from the end is a: S0 guesses the a, S1…S12
+	// count the symbols after it. Every set of the last 12 symbols is a state.
+	const distance = 12
+	nfa := NewNFA(namedStates(distance+1), Alphabet{"a", "b"})
+	nfa.AddTransition(0, 0, 0)
+	nfa.AddTransition(0, 1, 0)
+	nfa.AddTransition(0, 0, 1)
+	for state := 1; state < distance; state++ {
+		nfa.AddTransition(state, 0, state+1)
+		nfa.AddTransition(state, 1, state+1)
+	}
+	nfa.Finals[distance] = true
+
+	dfa, _ := Determinize(nfa)
+	if want := 1 << distance; len(dfa.States) != want {
+		t.Fatalf("DFA has %d states, want %d", len(dfa.States), want)
+	}
+	if minimized := MinimizeDFA(dfa); len(minimized.States) != len(dfa.States) {
+		t.Errorf("minimal DFA has %d states, want %d", len(minimized.States), len(dfa.States))
+	}
+
+	word := []string{"b", "a"}
+	for i := 1; i < distance; i++ {
+		word = append(word, "b")
+	}
+	if accepted, _ := dfa.Accepts(word); !accepted {
+		t.Errorf("DFA rejects %v", word)
+	}
+	if accepted, _ := dfa.Accepts(word[1:]); !accepted {
+		t.Errorf("DFA rejects %v", word[1:])
+	}
+	if accepted, _ := dfa.Accepts(word[:len(word)-1]); accepted {
+		t.Errorf("DFA accepts %v", word[:len(word)-1])
+	}
+}
@@ -0,0 +1,124 @@
+package automata
+
+import (
+	"fmt"
+	"slices"
+)
+
+type DFA struct {
+	States      []string
+	Alphabet    Alphabet
+	Start       int
+	Finals      []bool
+	Transitions [][]int
+}
+
+func NewDFA(states []string, alphabet Alphabet) *DFA {
+	return &DFA{
+		States:      slices.Clone(states),
+		Alphabet:    slices.Clone(alphabet),
+		Start:       0,
+		Finals:      make([]bool, len(states)),
+		Transitions: newTransitionTable(len(states), len(alphabet)),
+	}
+}
+
+func (d *DFA) StateIndex(name string) int {
+	return stateIndex(d.States, name)
+}
+
+func (d *DFA) Next(state, symbol int) int {
+	return d.Transitions[state][symbol]
+}
+
+func (d *DFA) IsComplete() bool {
+	for _, row := range d.Transitions {
+		if slices.Contains(row, NoState) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (d *DFA) Accepts(word []string) (bool, error) {
+	state := d.Start
+	for _, symbol := range word {
+		symbolIndex := d.Alphabet.Index(symbol)
+		if symbolIndex == -1 {
+			return false, fmt.Errorf("unknown input symbol %q", symbol)
+		}
+		state = d.Transitions[state][symbolIndex]
+		if state == NoState {
+			return false, nil
+		}
+	}
+
+	return d.Finals[state], nil
+}
+
+func (d *DFA) Validate() error {
+	if err := validateStates(d.States, d.Alphabet, d.Start); err != nil {
+		return err
+	}
+	if len(d.Finals) != len(d.States) {
+		return fmt.Errorf("dfa has %d final marks for %d states", len(d.Finals), len(d.States))
+	}
+	if len(d.Transitions) != len(d.States) {
+		return fmt.Errorf("dfa has %d transition rows for %d states", len(d.Transitions), len(d.States))
+	}
+	for from, row := range d.Transitions {
+		if len(row) != len(d.Alphabet) {
+			return fmt.Errorf("state %s has %d transitions for %d input symbols", d.States[from], len(row), len(d.Alphabet))
+		}
+		for symbol, target := range row {
+			if err := validateTarget(d.States, d.Alphabet, from, symbol, target); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *DFA) Clone() *DFA {
+	return &DFA{
+		States:      slices.Clone(d.States),
+		Alphabet:    slices.Clone(d.Alphabet),
+		Start:       d.Start,
+		Finals:      slices.Clone(d.Finals),
+		Transitions: cloneTransitionTable(d.Transitions),
+	}
+}
+
+func (d *DFA) ToNFA() *NFA {
+	n := NewNFA(d.States, d.Alphabet)
+	n.Start = d.Start
+	copy(n.Finals, d.Finals)
+	for from, row := range d.Transitions {
+		for symbol, target := range row {
+			if target != NoState {
+				n.AddTransition(from, symbol, target)
+			}
+		}
+	}
+
+	return n
+}
+
+// ToMoore treats acceptance as the output of a state, which is how NFAToDFA
+// marks final states with "F".
+func (d *DFA) ToMoore(finalOutput, otherOutput string) *Moore {
+	m := NewMoore(d.States, d.Alphabet)
+	m.Start = d.Start
+	m.Transitions = cloneTransitionTable(d.Transitions)
+	for i, final := range d.Finals {
+		if final {
+			m.Outputs[i] = finalOutput
+		} else {
+			m.Outputs[i] = otherOutput
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,118 @@
+// Package diag collects problems found in input files, each with the file,
+// line and column it points at, so a tool can report all of them at once
+// instead of stopping at the first.
+package diag
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+)
+
+func (s Severity) String() string {
+	if s == Warning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic points at a 1-based line and column; zero means unknown.
+type Diagnostic struct {
+	File     string
+	Line     int
+	Column   int
+	Severity Severity
+	Message  string
+}
+
+// Error formats the diagnostic as file:line:col: message, the form editors
+// and CI logs link to the source.
+func (d Diagnostic) Error() string {
+	var builder strings.Builder
+	for _, part := range []string{d.File, position(d.Line), position(d.Column)} {
+		if part != "" {
+			builder.WriteString(part + ":")
+		}
+	}
+	if builder.Len() > 0 {
+		builder.WriteByte(' ')
+	}
+	if d.Severity == Warning {
+		builder.WriteString("warning: ")
+	}
+	builder.WriteString(d.Message)
+
+	return builder.String()
+}
+
+func position(value int) string {
+	if value <= 0 {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// List is an error holding every diagnostic of one input.
+type List []Diagnostic
+
+func (l *List) Errorf(line, column int, format string, args ...any) {
+	*l = append(*l, Diagnostic{Line: line, Column: column, Severity: Error, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *List) Warnf(line, column int, format string, args ...any) {
+	*l = append(*l, Diagnostic{Line: line, Column: column, Severity: Warning, Message: fmt.Sprintf(format, args...)})
+}
+
+// SetFile fills in the file name of every diagnostic.
+func (l List) SetFile(file string) {
+	for i := range l {
+		l[i].File = file
+	}
+}
+
+// Sort orders the diagnostics by file, line and column.
+func (l List) Sort() {
+	slices.SortStableFunc(l, func(a, b Diagnostic) int {
+		if a.File != b.File {
+			return cmp.Compare(a.File, b.File)
+		}
+		if a.Line != b.Line {
+			return cmp.Compare(a.Line, b.Line)
+		}
+		return cmp.Compare(a.Column, b.Column)
+	})
+}
+
+func (l List) HasErrors() bool {
+	for _, d := range l {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the list as an error when it has errors and nil otherwise, so
+// warnings alone do not fail a read.
+func (l List) Err() error {
+	if l.HasErrors() {
+		return l
+	}
+	return nil
+}
+
+func (l List) Error() string {
+	lines := make([]string, len(l))
+	for i, d := range l {
+		lines[i] = d.Error()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,208 @@
+// Package document is the versioned JSON and YAML form of a machine, meant
+// for exchanging machines with tools that should not parse tables:
+//
+//	{
+//	  "version": 1,
+//	  "type": "moore",
+//	  "alphabet": ["x1", "x2"],
+//	  "states": ["s0", "s1"],
+//	  "start": "s0",
+//	  "outputs": {"s0": "y1", "s1": "y2"},
+//	  "transitions": [
+//	    {"from": "s0", "input": "x1", "to": "s1"},
+//	    ...
+//	  ]
+//	}
+//
+// Schema is the JSON Schema of the document.
+package document
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+// Version is written to every document; readers reject other versions.
+const Version = 1
+
+//go:embed schema.json
+var Schema []byte
+
+// Document lists states and symbols by name. Finals are only used by DFAs
+// and NFAs, Outputs only by Moore machines.
+type Document struct {
+	Version     int               `json:"version" yaml:"version"`
+	Type        table.Kind        `json:"type" yaml:"type"`
+	Alphabet    []string          `json:"alphabet" yaml:"alphabet"`
+	States      []string          `json:"states" yaml:"states"`
+	Start       string            `json:"start" yaml:"start"`
+	Finals      []string          `json:"finals,omitempty" yaml:"finals,omitempty"`
+	Outputs     map[string]string `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Transitions []Transition      `json:"transitions" yaml:"transitions"`
+}
+
+// Transition has either an Input or, in NFAs, Epsilon set. Output is the
+// Mealy output of the transition.
+type Transition struct {
+	From    string `json:"from" yaml:"from"`
+	Input   string `json:"input,omitempty" yaml:"input,omitempty"`
+	Epsilon bool   `json:"epsilon,omitempty" yaml:"epsilon,omitempty"`
+	To      string `json:"to" yaml:"to"`
+	Output  string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+func newDocument(kind table.Kind, states []string, alphabet automata.Alphabet, start int) *Document {
+	return &Document{
+		Version:     Version,
+		Type:        kind,
+		Alphabet:    slices.Clone([]string(alphabet)),
+		States:      slices.Clone(states),
+		Start:       states[start],
+		Transitions: make([]Transition, 0),
+	}
+}
+
+func finalNames(states []string, finals []bool) []string {
+	var names []string
+	for state, final := range finals {
+		if final {
+			names = append(names, states[state])
+		}
+	}
+	return names
+}
+
+func FromDFA(dfa *automata.DFA) *Document {
+	d := newDocument(table.KindDFA, dfa.States, dfa.Alphabet, dfa.Start)
+	d.Finals = finalNames(dfa.States, dfa.Finals)
+	for state, row := range dfa.Transitions {
+		for symbol, target := range row {
+			if target != automata.NoState {
+				d.Transitions = append(d.Transitions, Transition{From: dfa.States[state], Input: dfa.Alphabet[symbol], To: dfa.States[target]})
+			}
+		}
+	}
+	return d
+}
+
+func FromNFA(nfa *automata.NFA) *Document {
+	d := newDocument(table.KindNFA, nfa.States, nfa.Alphabet, nfa.Start)
+	d.Finals = finalNames(nfa.States, nfa.Finals)
+	for state, row := range nfa.Transitions {
+		for symbol, targets := range row {
+			for _, target := range targets {
+				d.Transitions = append(d.Transitions, Transition{From: nfa.States[state], Input: nfa.Alphabet[symbol], To: nfa.States[target]})
+			}
+		}
+		for _, target := range nfa.Epsilon[state] {
+			d.Transitions = append(d.Transitions, Transition{From: nfa.States[state], Epsilon: true, To: nfa.States[target]})
+		}
+	}
+	return d
+}
+
+func FromMoore(moore *automata.Moore) *Document {
+	d := newDocument(table.KindMoore, moore.States, moore.Alphabet, moore.Start)
+	d.Outputs = make(map[string]string, len(moore.States))
+	for state, name := range moore.States {
+		d.Outputs[name] = moore.Outputs[state]
+	}
+	for state, row := range moore.Transitions {
+		for symbol, target := range row {
+			if target != automata.NoState {
+				d.Transitions = append(d.Transitions, Transition{From: moore.States[state], Input: moore.Alphabet[symbol], To: moore.States[target]})
+			}
+		}
+	}
+	return d
+}
+
+func FromMealy(mealy *automata.Mealy) *Document {
+	d := newDocument(table.KindMealy, mealy.States, mealy.Alphabet, mealy.Start)
+	for state, row := range mealy.Transitions {
+		for symbol, transition := range row {
+			if transition.Target != automata.NoState {
+				d.Transitions = append(d.Transitions, Transition{
+					From:   mealy.States[state],
+					Input:  mealy.Alphabet[symbol],
+					To:     mealy.States[transition.Target],
+					Output: transition.Output,
+				})
+			}
+		}
+	}
+	return d
+}
+
+// FromRecords converts a machine table, detecting its kind when kind is
+// empty.
+func FromRecords(records [][]string, kind table.Kind) (*Document, error) {
+	if kind == "" {
+		kind = table.DetectKind(records)
+	}
+
+	switch kind {
+	case table.KindDFA:
+		dfa, err := table.ParseDFA(records)
+		if err != nil {
+			return nil, err
+		}
+		return FromDFA(dfa), nil
+	case table.KindNFA:
+		nfa, err := table.ParseNFA(records)
+		if err != nil {
+			return nil, err
+		}
+		return FromNFA(nfa), nil
+	case table.KindMoore:
+		moore, err := table.ParseMoore(records)
+		if err != nil {
+			return nil, err
+		}
+		return FromMoore(moore), nil
+	case table.KindMealy:
+		mealy, err := table.ParseMealy(records)
+		if err != nil {
+			return nil, err
+		}
+		return FromMealy(mealy), nil
+	}
+
+	return nil, fmt.Errorf("unknown machine type %q", kind)
+}
+
+// Records converts the document back to a machine table.
+func (d *Document) Records() ([][]string, error) {
+	switch d.Type {
+	case table.KindDFA:
+		dfa, err := d.DFA()
+		if err != nil {
+			return nil, err
+		}
+		return table.DFARecords(dfa), nil
+	case table.KindNFA:
+		nfa, err := d.NFA()
+		if err != nil {
+			return nil, err
+		}
+		return table.NFARecords(nfa), nil
+	case table.KindMoore:
+		moore, err := d.Moore()
+		if err != nil {
+			return nil, err
+		}
+		return table.MooreRecords(moore), nil
+	case table.KindMealy:
+		mealy, err := d.Mealy()
+		if err != nil {
+			return nil, err
+		}
+		return table.MealyRecords(mealy), nil
+	}
+
+	return nil, fmt.Errorf("unknown machine type %q", d.Type)
+}
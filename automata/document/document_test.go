@@ -0,0 +1,317 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+func testDFA() *automata.DFA {
+	dfa := automata.NewDFA([]string{"q0", "q1", "q2"}, automata.Alphabet{"a", "b"})
+	dfa.Start = 1
+	dfa.Finals[2] = true
+	dfa.Transitions[0] = []int{1, 2}
+	dfa.Transitions[1] = []int{1, automata.NoState}
+	dfa.Transitions[2] = []int{0, 2}
+	return dfa
+}
+
+func testNFA() *automata.NFA {
+	nfa := automata.NewNFA([]string{"q0", "q1", "q2"}, automata.Alphabet{"a", "b"})
+	nfa.Finals[2] = true
+	nfa.AddTransition(0, 0, 0)
+	nfa.AddTransition(0, 0, 1)
+	nfa.AddTransition(1, 1, 2)
+	nfa.AddEpsilon(0, 2)
+	nfa.AddEpsilon(2, 1)
+	return nfa
+}
+
+func testMoore() *automata.Moore {
+	moore := automata.NewMoore([]string{"s0", "s1"}, automata.Alphabet{"x1", "x2"})
+	moore.Outputs = []string{"y1", "y2"}
+	moore.Transitions[0] = []int{1, 0}
+	moore.Transitions[1] = []int{automata.NoState, 1}
+	return moore
+}
+
+func testMealy() *automata.Mealy {
+	mealy := automata.NewMealy([]string{"a0", "a1", "a2"}, automata.Alphabet{"z1", "z2"})
+	mealy.Start = 2
+	mealy.Transitions[0] = []automata.MealyTransition{{Target: 1, Output: "w1"}, {Target: 0, Output: "w2"}}
+	mealy.Transitions[1] = []automata.MealyTransition{{Target: 2, Output: "w1"}, {Target: automata.NoState}}
+	mealy.Transitions[2] = []automata.MealyTransition{{Target: 0, Output: "w2"}, {Target: 2, Output: "w1"}}
+	return mealy
+}
+
+// machine converts d back to the machine of its type.
+func machine(d *Document) (any, error) {
+	switch d.Type {
+	case table.KindDFA:
+		return d.DFA()
+	case table.KindNFA:
+		return d.NFA()
+	case table.KindMoore:
+		return d.Moore()
+	case table.KindMealy:
+		return d.Mealy()
+	}
+	return nil, fmt.Errorf("unknown machine type %q", d.Type)
+}
+
+func roundTrip(t *testing.T, d *Document, format Format) *Document {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, d, format); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("%s: %v", format, err)
+	}
+	return read
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		document *Document
+		want     any
+	}{
+		{FromDFA(testDFA()), testDFA()},
+		{FromNFA(testNFA()), testNFA()},
+		{FromMoore(testMoore()), testMoore()},
+		{FromMealy(testMealy()), testMealy()},
+	}
+
+	for _, tt := range tests {
+		for _, format := range []Format{FormatJSON, FormatYAML} {
+			d := roundTrip(t, tt.document, format)
+			if !reflect.DeepEqual(d, tt.document) {
+				t.Errorf("%s %s: read back %+v, want %+v", tt.document.Type, format, d, tt.document)
+			}
+			got, err := machine(d)
+			if err != nil {
+				t.Fatalf("%s %s: %v", d.Type, format, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s %s: machine %+v, want %+v", d.Type, format, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestFromHeaderTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       any
+	}{
+		{"moore", "2 2 2\ny1 y2\ns1 s0\ns0 s1\n", func() any {
+			moore := automata.NewMoore([]string{"s0", "s1"}, automata.Alphabet{"x0", "x1"})
+			moore.Outputs = []string{"y1", "y2"}
+			moore.Transitions[0] = []int{1, 0}
+			moore.Transitions[1] = []int{0, 1}
+			return moore
+		}()},
+		{"mealy", "2 1 1\ns1/y0 s0/y1\n", func() any {
+			mealy := automata.NewMealy([]string{"s0", "s1"}, automata.Alphabet{"x0"})
+			mealy.Transitions[0][0] = automata.MealyTransition{Target: 1, Output: "y0"}
+			mealy.Transitions[1][0] = automata.MealyTransition{Target: 0, Output: "y1"}
+			return mealy
+		}()},
+	}
+
+	for _, tt := range tests {
+		d, err := FromHeaderText(strings.NewReader(tt.text))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, format := range []Format{FormatJSON, FormatYAML} {
+			got, err := machine(roundTrip(t, d, format))
+			if err != nil {
+				t.Fatalf("%s %s: %v", tt.name, format, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s %s: machine %+v, want %+v", tt.name, format, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestFromHeaderTextErrors(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       []string
+	}{
+		{"empty", "", []string{"missing header line"}},
+		{"header", "2 x 3\n", []string{`"x" is not a number`}},
+		{"type", "1 1 3\ns0\n", []string{"unknown machine type 3"}},
+		{"rows", "2 1 1\ns1/y0\nextra/y\n", []string{"row must have 2 entries instead of 1", "extra row"}},
+		{"targets", "2 1 1\ns1/y0 s9/y1\n", []string{`unknown target state "s9"`}},
+	}
+
+	for _, tt := range tests {
+		_, err := FromHeaderText(strings.NewReader(tt.text))
+		for _, want := range tt.want {
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, want)
+			}
+		}
+	}
+}
+
+func TestWriteMatchesSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []*Document{FromDFA(testDFA()), FromNFA(testNFA()), FromMoore(testMoore()), FromMealy(testMealy())} {
+		var buf bytes.Buffer
+		if err := Write(&buf, d, FormatJSON); err != nil {
+			t.Fatal(err)
+		}
+		var value any
+		if err := json.Unmarshal(buf.Bytes(), &value); err != nil {
+			t.Fatal(err)
+		}
+		if err := validate(schema, schema, value); err != nil {
+			t.Errorf("%s document: %v\n%s", d.Type, err, buf.String())
+		}
+	}
+
+	// The schema rejects what the readers reject.
+	invalid := []string{
+		`{"version": 2, "type": "dfa", "alphabet": [], "states": ["q"], "start": "q", "transitions": []}`,
+		`{"version": 1, "type": "moore", "alphabet": [], "states": ["q"], "start": "q", "transitions": []}`,
+		`{"version": 1, "type": "mealy", "alphabet": [], "states": ["q"], "start": "q", "finals": ["q"], "transitions": []}`,
+		`{"version": 1, "type": "dfa", "alphabet": ["a"], "states": ["q"], "start": "q", "transitions": [{"from": "q", "epsilon": true, "to": "q"}]}`,
+		`{"version": 1, "type": "dfa", "alphabet": ["a"], "states": ["q"], "start": "q", "transitions": [{"from": "q", "input": "a", "to": "q", "output": "y"}]}`,
+		`{"version": 1, "type": "dfa", "alphabet": ["a"], "states": ["q"], "start": "q", "transitions": [], "extra": 1}`,
+	}
+	for _, text := range invalid {
+		var value any
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			t.Fatal(err)
+		}
+		if err := validate(schema, schema, value); err == nil {
+			t.Errorf("schema accepts %s", text)
+		}
+	}
+}
+
+// validate checks value against the keywords of JSON Schema that schema.json
+// uses; root resolves the #/$defs/ references.
+func validate(root, schema map[string]any, value any) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		return validate(root, root["$defs"].(map[string]any)[name].(map[string]any), value)
+	}
+
+	if kind, ok := schema["type"].(string); ok {
+		var matches bool
+		switch kind {
+		case "object":
+			_, matches = value.(map[string]any)
+		case "array":
+			_, matches = value.([]any)
+		case "string":
+			_, matches = value.(string)
+		}
+		if !matches {
+			return fmt.Errorf("%v is not of type %s", value, kind)
+		}
+	}
+	if want, ok := schema["const"]; ok && !reflect.DeepEqual(value, want) {
+		return fmt.Errorf("%v is not %v", value, want)
+	}
+	if values, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(values, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+		return fmt.Errorf("%v is not one of %v", value, values)
+	}
+
+	if object, ok := value.(map[string]any); ok {
+		for _, name := range asSlice(schema["required"]) {
+			if _, ok := object[name.(string)]; !ok {
+				return fmt.Errorf("missing %s", name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range object {
+			if propertySchema, ok := properties[name].(map[string]any); ok {
+				if err := validate(root, propertySchema, property); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("unknown property %s", name)
+				}
+			case map[string]any:
+				if err := validate(root, additional, property); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+		}
+	}
+
+	if array, ok := value.([]any); ok {
+		if minItems, ok := schema["minItems"].(float64); ok && len(array) < int(minItems) {
+			return fmt.Errorf("want at least %v items, got %d", minItems, len(array))
+		}
+		for i, item := range array {
+			if unique, _ := schema["uniqueItems"].(bool); unique && slices.ContainsFunc(array[:i], func(v any) bool { return reflect.DeepEqual(v, item) }) {
+				return fmt.Errorf("%v is listed twice", item)
+			}
+			if items, ok := schema["items"].(map[string]any); ok {
+				if err := validate(root, items, item); err != nil {
+					return fmt.Errorf("item %d: %w", i+1, err)
+				}
+			}
+		}
+	}
+
+	for _, sub := range asSlice(schema["allOf"]) {
+		if err := validate(root, sub.(map[string]any), value); err != nil {
+			return err
+		}
+	}
+	if anyOf := asSlice(schema["anyOf"]); len(anyOf) > 0 && countValid(root, anyOf, value) == 0 {
+		return fmt.Errorf("%v matches none of anyOf", value)
+	}
+	if oneOf := asSlice(schema["oneOf"]); len(oneOf) > 0 && countValid(root, oneOf, value) != 1 {
+		return fmt.Errorf("%v does not match exactly one of oneOf", value)
+	}
+	if not, ok := schema["not"].(map[string]any); ok && validate(root, not, value) == nil {
+		return fmt.Errorf("%v matches a forbidden schema", value)
+	}
+	if condition, ok := schema["if"].(map[string]any); ok && validate(root, condition, value) == nil {
+		if then, ok := schema["then"].(map[string]any); ok {
+			return validate(root, then, value)
+		}
+	}
+
+	return nil
+}
+
+func asSlice(value any) []any {
+	values, _ := value.([]any)
+	return values
+}
+
+func countValid(root map[string]any, schemas []any, value any) int {
+	count := 0
+	for _, schema := range schemas {
+		if validate(root, schema.(map[string]any), value) == nil {
+			count++
+		}
+	}
+	return count
+}
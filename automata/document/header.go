@@ -0,0 +1,168 @@
+package document
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/diag"
+)
+
+// Machine types of the header line in the mooreMealyConversion files.
+const (
+	headerMealy = 1
+	headerMoore = 2
+)
+
+// headerLine is a non-empty line split into fields with their 1-based
+// columns.
+type headerLine struct {
+	number  int
+	fields  []string
+	columns []int
+}
+
+func splitFields(text string, number int) headerLine {
+	l := headerLine{number: number}
+	start := -1
+	for i, c := range text + " " {
+		switch {
+		case unicode.IsSpace(c) && start != -1:
+			l.fields = append(l.fields, text[start:i])
+			l.columns = append(l.columns, start+1)
+			start = -1
+		case !unicode.IsSpace(c) && start == -1:
+			start = i
+		}
+	}
+	return l
+}
+
+// FromHeaderText converts the files of mooreMealyConversion. Their first line
+// is "<states> <inputs> <type>" with type 1 for Mealy and 2 for Moore. A
+// Moore machine continues with a line of outputs; then comes one line per
+// input of targets, "s1/y0" style for Mealy. States are s0, s1, … with s0
+// the start, inputs are x0, x1, …
+//
+// Problems are returned as a diag.List holding all of them.
+func FromHeaderText(r io.Reader) (*Document, error) {
+	var lines []headerLine
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		if l := splitFields(scanner.Text(), number); len(l.fields) > 0 {
+			lines = append(lines, l)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var list diag.List
+	if len(lines) == 0 {
+		list.Errorf(1, 0, "missing header line <states> <inputs> <type>")
+		return nil, list
+	}
+
+	header := lines[0]
+	if len(header.fields) != 3 {
+		list.Errorf(header.number, 1, "header must be <states> <inputs> <type>, got %d fields", len(header.fields))
+		return nil, list
+	}
+	var sizes [3]int
+	for i, field := range header.fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 {
+			list.Errorf(header.number, header.columns[i], "%q is not a number", field)
+		}
+		sizes[i] = value
+	}
+	statesNum, inputsNum, machineType := sizes[0], sizes[1], sizes[2]
+	if list.HasErrors() {
+		return nil, list
+	}
+	if statesNum == 0 {
+		list.Errorf(header.number, header.columns[0], "machine has no states")
+	}
+	if machineType != headerMealy && machineType != headerMoore {
+		list.Errorf(header.number, header.columns[2], "unknown machine type %d, want 1 for Mealy or 2 for Moore", machineType)
+	}
+	if list.HasErrors() {
+		return nil, list
+	}
+
+	states := make([]string, statesNum)
+	for i := range states {
+		states[i] = "s" + strconv.Itoa(i)
+	}
+	alphabet := make(automata.Alphabet, inputsNum)
+	for i := range alphabet {
+		alphabet[i] = "x" + strconv.Itoa(i)
+	}
+
+	rows := lines[1:]
+	rowsNum := inputsNum
+	if machineType == headerMoore {
+		rowsNum++
+	}
+	if len(rows) < rowsNum {
+		list.Errorf(lines[len(lines)-1].number+1, 0, "missing %d of %d rows after the header", rowsNum-len(rows), rowsNum)
+	}
+	for _, l := range rows[min(rowsNum, len(rows)):] {
+		list.Errorf(l.number, 1, "extra row, the header declares %d", rowsNum)
+	}
+	rows = rows[:min(rowsNum, len(rows))]
+	for _, l := range rows {
+		if len(l.fields) != statesNum {
+			column := l.columns[min(statesNum, len(l.columns)-1)]
+			list.Errorf(l.number, column, "row must have %d entries instead of %d", statesNum, len(l.fields))
+		}
+	}
+
+	target := func(l headerLine, i int, name string) int {
+		state := slices.Index(states, name)
+		if state == -1 {
+			list.Errorf(l.number, l.columns[i], "unknown target state %q", name)
+		}
+		return state
+	}
+
+	var d *Document
+	if machineType == headerMealy {
+		mealy := automata.NewMealy(states, alphabet)
+		for symbol, l := range rows {
+			for state, field := range l.fields[:min(len(l.fields), statesNum)] {
+				name, output, ok := strings.Cut(field, "/")
+				if !ok {
+					list.Errorf(l.number, l.columns[state], "%q must be <state>/<output>", field)
+					continue
+				}
+				if output == "" {
+					list.Errorf(l.number, l.columns[state], "transition to %s has no output", name)
+				}
+				mealy.Transitions[state][symbol] = automata.MealyTransition{Target: target(l, state, name), Output: output}
+			}
+		}
+		d = FromMealy(mealy)
+	} else {
+		moore := automata.NewMoore(states, alphabet)
+		if len(rows) > 0 {
+			copy(moore.Outputs, rows[0].fields)
+			rows = rows[1:]
+		}
+		for symbol, l := range rows {
+			for state, name := range l.fields[:min(len(l.fields), statesNum)] {
+				moore.Transitions[state][symbol] = target(l, state, name)
+			}
+		}
+		d = FromMoore(moore)
+	}
+
+	if list.HasErrors() {
+		return nil, list
+	}
+	return d, nil
+}
@@ -0,0 +1,117 @@
+package document
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	}
+
+	return "", fmt.Errorf("unknown document format %q", name)
+}
+
+// Read decodes a JSON document, or a YAML one when the input does not start
+// with {. Unknown fields are errors, so typos do not get lost silently.
+func Read(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var d Document
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&d)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&d)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid machine document: %w", err)
+	}
+	if d.Version != Version {
+		return nil, fmt.Errorf("unsupported document version %d, want %d", d.Version, Version)
+	}
+
+	return &d, nil
+}
+
+func ReadFile(filePath string) (*Document, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+func Write(w io.Writer, d *Document, format Format) error {
+	writer := bufio.NewWriter(w)
+
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(d); err != nil {
+			return err
+		}
+	case FormatYAML:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(d); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown document format %q", format)
+	}
+
+	return writer.Flush()
+}
+
+// WriteFile picks the format by the file extension, JSON unless it is .yaml
+// or .yml.
+func WriteFile(filePath string, d *Document) error {
+	format := FormatJSON
+	if extension := strings.ToLower(filepath.Ext(filePath)); extension == ".yaml" || extension == ".yml" {
+		format = FormatYAML
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = Write(file, d, format); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
@@ -0,0 +1,211 @@
+package document
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+)
+
+// check validates the parts every machine type shares.
+func (d *Document) check(kind table.Kind) error {
+	if d.Version != Version {
+		return fmt.Errorf("unsupported document version %d, want %d", d.Version, Version)
+	}
+	if d.Type != kind {
+		return fmt.Errorf("document holds a %s machine, not a %s one", d.Type, kind)
+	}
+	if len(d.States) == 0 {
+		return fmt.Errorf("document has no states")
+	}
+	if name, ok := duplicate(d.States); ok {
+		return fmt.Errorf("state %q is listed twice", name)
+	}
+	if name, ok := duplicate(d.Alphabet); ok {
+		return fmt.Errorf("input %q is listed twice", name)
+	}
+	if !slices.Contains(d.States, d.Start) {
+		return fmt.Errorf("unknown start state %q", d.Start)
+	}
+	if len(d.Finals) > 0 && kind != table.KindDFA && kind != table.KindNFA {
+		return fmt.Errorf("%s machines have no final states", kind)
+	}
+	if len(d.Outputs) > 0 && kind != table.KindMoore {
+		return fmt.Errorf("%s machines have no state outputs", kind)
+	}
+
+	for i, transition := range d.Transitions {
+		if transition.Epsilon && (kind != table.KindNFA || transition.Input != "") {
+			return fmt.Errorf("transition %d: only NFA transitions without input can be epsilon", i+1)
+		}
+		if transition.Output != "" && kind != table.KindMealy {
+			return fmt.Errorf("transition %d: only Mealy transitions have outputs", i+1)
+		}
+	}
+
+	return nil
+}
+
+func duplicate(names []string) (string, bool) {
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			return name, true
+		}
+		seen[name] = struct{}{}
+	}
+	return "", false
+}
+
+// resolve turns the names of the i-th transition into indices; symbol is
+// automata.NoState for epsilon transitions.
+func (d *Document) resolve(i int, alphabet automata.Alphabet) (from, symbol, to int, err error) {
+	transition := d.Transitions[i]
+	if from = slices.Index(d.States, transition.From); from == -1 {
+		return 0, 0, 0, fmt.Errorf("transition %d: unknown state %q", i+1, transition.From)
+	}
+	if to = slices.Index(d.States, transition.To); to == -1 {
+		return 0, 0, 0, fmt.Errorf("transition %d: unknown state %q", i+1, transition.To)
+	}
+	if transition.Epsilon {
+		return from, automata.NoState, to, nil
+	}
+	if symbol = alphabet.Index(transition.Input); symbol == -1 {
+		return 0, 0, 0, fmt.Errorf("transition %d: unknown input %q", i+1, transition.Input)
+	}
+	return from, symbol, to, nil
+}
+
+func (d *Document) finals() ([]bool, error) {
+	finals := make([]bool, len(d.States))
+	for _, name := range d.Finals {
+		state := slices.Index(d.States, name)
+		if state == -1 {
+			return nil, fmt.Errorf("unknown final state %q", name)
+		}
+		finals[state] = true
+	}
+	return finals, nil
+}
+
+// deterministic reports the second transition from the same state by the
+// same input.
+func deterministic(i int, transition Transition, current, target int) error {
+	if current != automata.NoState && current != target {
+		return fmt.Errorf("transition %d: state %s already goes somewhere else by %s", i+1, transition.From, transition.Input)
+	}
+	return nil
+}
+
+func (d *Document) DFA() (*automata.DFA, error) {
+	if err := d.check(table.KindDFA); err != nil {
+		return nil, err
+	}
+
+	dfa := automata.NewDFA(d.States, d.Alphabet)
+	dfa.Start = slices.Index(d.States, d.Start)
+	finals, err := d.finals()
+	if err != nil {
+		return nil, err
+	}
+	dfa.Finals = finals
+
+	for i, transition := range d.Transitions {
+		from, symbol, to, err := d.resolve(i, dfa.Alphabet)
+		if err != nil {
+			return nil, err
+		}
+		if err = deterministic(i, transition, dfa.Transitions[from][symbol], to); err != nil {
+			return nil, err
+		}
+		dfa.Transitions[from][symbol] = to
+	}
+
+	return dfa, nil
+}
+
+func (d *Document) NFA() (*automata.NFA, error) {
+	if err := d.check(table.KindNFA); err != nil {
+		return nil, err
+	}
+
+	nfa := automata.NewNFA(d.States, d.Alphabet)
+	nfa.Start = slices.Index(d.States, d.Start)
+	finals, err := d.finals()
+	if err != nil {
+		return nil, err
+	}
+	nfa.Finals = finals
+
+	for i := range d.Transitions {
+		from, symbol, to, err := d.resolve(i, nfa.Alphabet)
+		if err != nil {
+			return nil, err
+		}
+		if symbol == automata.NoState {
+			nfa.AddEpsilon(from, to)
+		} else {
+			nfa.AddTransition(from, symbol, to)
+		}
+	}
+
+	return nfa, nil
+}
+
+func (d *Document) Moore() (*automata.Moore, error) {
+	if err := d.check(table.KindMoore); err != nil {
+		return nil, err
+	}
+
+	moore := automata.NewMoore(d.States, d.Alphabet)
+	moore.Start = slices.Index(d.States, d.Start)
+	for name := range d.Outputs {
+		if !slices.Contains(d.States, name) {
+			return nil, fmt.Errorf("output of unknown state %q", name)
+		}
+	}
+	for state, name := range d.States {
+		output, ok := d.Outputs[name]
+		if !ok {
+			return nil, fmt.Errorf("state %q has no output", name)
+		}
+		moore.Outputs[state] = output
+	}
+
+	for i, transition := range d.Transitions {
+		from, symbol, to, err := d.resolve(i, moore.Alphabet)
+		if err != nil {
+			return nil, err
+		}
+		if err = deterministic(i, transition, moore.Transitions[from][symbol], to); err != nil {
+			return nil, err
+		}
+		moore.Transitions[from][symbol] = to
+	}
+
+	return moore, nil
+}
+
+func (d *Document) Mealy() (*automata.Mealy, error) {
+	if err := d.check(table.KindMealy); err != nil {
+		return nil, err
+	}
+
+	mealy := automata.NewMealy(d.States, d.Alphabet)
+	mealy.Start = slices.Index(d.States, d.Start)
+
+	for i, transition := range d.Transitions {
+		from, symbol, to, err := d.resolve(i, mealy.Alphabet)
+		if err != nil {
+			return nil, err
+		}
+		next := automata.MealyTransition{Target: to, Output: transition.Output}
+		if current := mealy.Transitions[from][symbol]; current.Target != automata.NoState && current != next {
+			return nil, fmt.Errorf("transition %d: state %s already goes somewhere else by %s", i+1, transition.From, transition.Input)
+		}
+		mealy.Transitions[from][symbol] = next
+	}
+
+	return mealy, nil
+}
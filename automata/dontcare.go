@@ -0,0 +1,186 @@
+package automata
+
+// MinimizeMooreDontCare minimizes a Moore machine whose missing transitions
+// are don't-cares: any behaviour is fine after them, so states may merge when
+// their outputs match and their defined transitions lead to states that may
+// merge as well. The result behaves like m on every word m defines.
+//
+// Finding the smallest such machine is NP-hard. Here compatible states are
+// grouped greedily in state order, and a group whose transitions fall apart is
+// split, so the result is small but not always minimal. States are named q0,
+// q1, … in breadth-first order from the start state.
+func MinimizeMooreDontCare(m *Moore) *Moore {
+	compatible := func(p, q int) bool {
+		return m.Outputs[p] == m.Outputs[q]
+	}
+	_, order, next := mergeCompatible(m.Transitions, m.Start, compatible)
+
+	minimized := NewMoore(quotientNames(len(order)), m.Alphabet)
+	for class, first := range order {
+		minimized.Outputs[class] = m.Outputs[first]
+		minimized.Transitions[class] = next[class]
+	}
+
+	return minimized
+}
+
+// MinimizeMealyDontCare is MinimizeMooreDontCare for Mealy machines, where
+// states may merge when they give the same output on every input both of
+// them specify an output for; a DontCare output matches any other.
+// ReduceMealy searches for a smaller result.
+func MinimizeMealyDontCare(m *Mealy) *Mealy {
+	minimized, _ := mealyFromCover(m, greedyCover(m))
+	return minimized
+}
+
+// mergeCompatible groups the reachable states into classes of pairwise
+// compatible states whose defined transitions by each symbol lead into a
+// single class. It returns the class of every state numbered in breadth-first
+// order from the start, NoState for unreachable ones, a state of every class
+// and the transitions between the classes.
+func mergeCompatible(transitions [][]int, start int, compatible func(p, q int) bool) (classes, order []int, next [][]int) {
+	reachable := reachableStates(transitions, start)
+	states := make([]int, 0, len(transitions))
+	for _, state := range StartFirst(len(transitions), start) {
+		if reachable[state] {
+			states = append(states, state)
+		}
+	}
+
+	incompatible := incompatiblePairs(transitions, states, compatible)
+	for {
+		classes = groupCompatible(states, len(transitions), incompatible)
+		if !splitOpenClasses(transitions, states, classes, incompatible) {
+			break
+		}
+	}
+
+	// The transitions of a class are the defined transitions of its states,
+	// which all lead into one class; the classes are then numbered like the
+	// states of the other minimizers.
+	classesNum := 0
+	for _, class := range classes {
+		classesNum = max(classesNum, class+1)
+	}
+	classTransitions := newTransitionTable(classesNum, len(transitions[start]))
+	for _, state := range states {
+		for symbol, target := range transitions[state] {
+			if target != NoState {
+				classTransitions[classes[state]][symbol] = classes[target]
+			}
+		}
+	}
+	identity := make([]int, classesNum)
+	for class := range identity {
+		identity[class] = class
+	}
+	classOrder, next := quotient(classTransitions, classes[start], identity)
+
+	numbers := make([]int, classesNum)
+	for number, class := range classOrder {
+		numbers[class] = number
+	}
+	order = make([]int, len(classOrder))
+	for i := len(states) - 1; i >= 0; i-- {
+		state := states[i]
+		classes[state] = numbers[classes[state]]
+		order[classes[state]] = state
+	}
+
+	return classes, order, next
+}
+
+// incompatiblePairs marks the pairs of states that can never merge: those
+// compatible rejects and those leading by some symbol to such a pair.
+func incompatiblePairs(transitions [][]int, states []int, compatible func(p, q int) bool) [][]bool {
+	incompatible := make([][]bool, len(transitions))
+	for state := range incompatible {
+		incompatible[state] = make([]bool, len(transitions))
+	}
+	for i, p := range states {
+		for _, q := range states[i+1:] {
+			if !compatible(p, q) {
+				incompatible[p][q], incompatible[q][p] = true, true
+			}
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i, p := range states {
+			for _, q := range states[i+1:] {
+				if incompatible[p][q] {
+					continue
+				}
+				for symbol, left := range transitions[p] {
+					right := transitions[q][symbol]
+					if left != NoState && right != NoState && incompatible[left][right] {
+						incompatible[p][q], incompatible[q][p] = true, true
+						changed = true
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return incompatible
+}
+
+// groupCompatible puts every state into the first class all of whose states
+// it is compatible with.
+func groupCompatible(states []int, statesNum int, incompatible [][]bool) []int {
+	classes := make([]int, statesNum)
+	for state := range classes {
+		classes[state] = NoState
+	}
+
+	var members [][]int
+	for _, state := range states {
+		class := 0
+		for ; class < len(members); class++ {
+			fits := true
+			for _, member := range members[class] {
+				if incompatible[state][member] {
+					fits = false
+					break
+				}
+			}
+			if fits {
+				break
+			}
+		}
+		if class == len(members) {
+			members = append(members, nil)
+		}
+		members[class] = append(members[class], state)
+		classes[state] = class
+	}
+
+	return classes
+}
+
+// splitOpenClasses marks as incompatible the states of a class whose
+// transitions by a symbol lead into different classes, and reports whether
+// there were any. Every round marks more pairs, so grouping again ends at the
+// latest with classes of single states, which are always closed.
+func splitOpenClasses(transitions [][]int, states, classes []int, incompatible [][]bool) bool {
+	split := false
+	for i, p := range states {
+		for _, q := range states[i+1:] {
+			if classes[p] != classes[q] || incompatible[p][q] {
+				continue
+			}
+			for symbol, left := range transitions[p] {
+				right := transitions[q][symbol]
+				if left != NoState && right != NoState && classes[left] != classes[right] {
+					incompatible[p][q], incompatible[q][p] = true, true
+					split = true
+					break
+				}
+			}
+		}
+	}
+
+	return split
+}
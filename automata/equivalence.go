@@ -0,0 +1,182 @@
+package automata
+
+import (
+	"slices"
+	"strings"
+)
+
+// Deterministic lists the machines Equivalent can compare.
+type Deterministic interface {
+	*DFA | *Moore | *Mealy
+}
+
+// Difference is a shortest input word on which two machines behave
+// differently, with what each of them produced at the end of it.
+type Difference struct {
+	Word  []string
+	Left  string
+	Right string
+}
+
+func (d *Difference) String() string {
+	return "word \"" + strings.Join(d.Word, " ") + "\": " + d.Left + " vs " + d.Right
+}
+
+// Equivalent compares two machines by a breadth-first walk over pairs of
+// states and returns nil when no input word tells them apart. Symbols missing
+// from one alphabet behave like missing transitions. A DFA rejects after a
+// missing transition; a Moore or Mealy machine differs there from one that
+// has the transition, whatever its output, and has no transitions after it.
+func Equivalent[M Deterministic](a, b M) *Difference {
+	return compareBehaviours(behaviourOf(a), behaviourOf(b))
+}
+
+// undefinedOutput shows a missing transition in a Difference.
+const undefinedOutput = "no transition"
+
+type behaviour struct {
+	alphabet Alphabet
+	start    int
+	output   func(state int) string
+	// next is the target and output of a transition and whether there is one
+	// at all; a Mealy transition may have an output but no target.
+	next func(state, symbol int) (int, string, bool)
+}
+
+func behaviourOf(machine any) behaviour {
+	switch m := machine.(type) {
+	case *DFA:
+		dead := len(m.States)
+		return behaviour{
+			alphabet: m.Alphabet,
+			start:    m.Start,
+			output: func(state int) string {
+				return acceptance(state != dead && m.Finals[state])
+			},
+			next: func(state, symbol int) (int, string, bool) {
+				if state == dead || symbol == NoState || m.Transitions[state][symbol] == NoState {
+					return dead, "", true
+				}
+				return m.Transitions[state][symbol], "", true
+			},
+		}
+	case *Moore:
+		return behaviour{
+			alphabet: m.Alphabet,
+			start:    m.Start,
+			output: func(state int) string {
+				return m.Outputs[state]
+			},
+			next: func(state, symbol int) (int, string, bool) {
+				if state == NoState || symbol == NoState || m.Transitions[state][symbol] == NoState {
+					return NoState, "", false
+				}
+				return m.Transitions[state][symbol], "", true
+			},
+		}
+	case *Mealy:
+		return behaviour{
+			alphabet: m.Alphabet,
+			start:    m.Start,
+			output: func(state int) string {
+				return ""
+			},
+			next: func(state, symbol int) (int, string, bool) {
+				if state == NoState || symbol == NoState {
+					return NoState, "", false
+				}
+				transition := m.Transitions[state][symbol]
+				return transition.Target, transition.Output, transition.Target != NoState || transition.HasOutput()
+			},
+		}
+	}
+
+	panic("automata: unsupported machine type")
+}
+
+// observation is what a machine shows after a transition: the transition
+// output of a Mealy machine or the output of the reached state otherwise.
+type observation struct {
+	output  string
+	defined bool
+}
+
+func (b behaviour) observe(next int, output string, defined bool) observation {
+	if !defined {
+		return observation{}
+	}
+	if next == NoState {
+		return observation{output: output, defined: true}
+	}
+
+	return observation{output: output + b.output(next), defined: true}
+}
+
+func (o observation) String() string {
+	if !o.defined {
+		return undefinedOutput
+	}
+
+	return o.output
+}
+
+func compareBehaviours(a, b behaviour) *Difference {
+	symbols := append(Alphabet{}, a.alphabet...)
+	for _, symbol := range b.alphabet {
+		if symbols.Index(symbol) == -1 {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	type pair struct {
+		left  int
+		right int
+	}
+	type visit struct {
+		parent pair
+		symbol string
+	}
+
+	start := pair{a.start, b.start}
+	visited := make(map[pair]visit)
+	wordTo := func(p pair, symbol string) []string {
+		word := []string{symbol}
+		for p != start {
+			word = append(word, visited[p].symbol)
+			p = visited[p].parent
+		}
+		slices.Reverse(word)
+		return word
+	}
+
+	if left, right := a.output(a.start), b.output(b.start); left != right {
+		return &Difference{Word: []string{}, Left: left, Right: right}
+	}
+	visited[start] = visit{parent: start}
+	queue := []pair{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, symbol := range symbols {
+			leftNext, leftOutput, leftDefined := a.next(current.left, a.alphabet.Index(symbol))
+			rightNext, rightOutput, rightDefined := b.next(current.right, b.alphabet.Index(symbol))
+			left, right := a.observe(leftNext, leftOutput, leftDefined), b.observe(rightNext, rightOutput, rightDefined)
+			if left != right {
+				return &Difference{Word: wordTo(current, symbol), Left: left.String(), Right: right.String()}
+			}
+
+			// A side without a next state has no transitions after this one,
+			// so the pair is walked on only while the other side has one.
+			next := pair{leftNext, rightNext}
+			if _, ok := visited[next]; ok || leftNext == NoState && rightNext == NoState {
+				continue
+			}
+			visited[next] = visit{parent: current, symbol: symbol}
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
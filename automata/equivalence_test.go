@@ -0,0 +1,58 @@
+package automata
+
+import "testing"
+
+// mealyOf builds a Mealy machine over the single input x from the
+// transitions of its states.
+func mealyOf(states []string, transitions ...MealyTransition) *Mealy {
+	m := NewMealy(states, Alphabet{"x"})
+	for state, transition := range transitions {
+		m.Transitions[state][0] = transition
+	}
+
+	return m
+}
+
+func TestEquivalentMissingTransitionAgainstDashOutput(t *testing.T) {
+	states := []string{"s0", "s1"}
+	// x;s1/-;s0/1 against x;-;s0/1
+	left := mealyOf(states, MealyTransition{Target: 1, Output: "-"}, MealyTransition{Target: 0, Output: "1"})
+	right := mealyOf(states, MealyTransition{Target: NoState}, MealyTransition{Target: 0, Output: "1"})
+
+	difference := Equivalent(left, right)
+	if difference == nil {
+		t.Fatal("a transition with the output - is equivalent to a missing one")
+	}
+	if len(difference.Word) != 1 || difference.Left != "-" || difference.Right != undefinedOutput {
+		t.Errorf("difference is %v", difference)
+	}
+}
+
+func TestEquivalentMealyWithoutNextState(t *testing.T) {
+	states := []string{"s0", "s1"}
+	// x;-/0;- against x;s1/0;-: s1 has no transitions, like no state at all.
+	left := mealyOf(states, MealyTransition{Target: NoState, Output: "0"}, MealyTransition{Target: NoState})
+	right := mealyOf(states, MealyTransition{Target: 1, Output: "0"}, MealyTransition{Target: NoState})
+	if difference := Equivalent(left, right); difference != nil {
+		t.Errorf("machines differ on %v", difference)
+	}
+
+	// x;-/0;- against x;s1/0;s1/1: s1 goes on.
+	right.Transitions[1][0] = MealyTransition{Target: 1, Output: "1"}
+	difference := Equivalent(left, right)
+	if difference == nil || len(difference.Word) != 2 {
+		t.Errorf("difference is %v, want one on x x", difference)
+	}
+}
+
+func TestEquivalentMooreMissingTransition(t *testing.T) {
+	left := NewMoore([]string{"s0", "s1"}, Alphabet{"x"})
+	left.Outputs = []string{"0", "-"}
+	left.Transitions[0][0] = 1
+	right := NewMoore([]string{"s0"}, Alphabet{"x"})
+	right.Outputs = []string{"0"}
+
+	if difference := Equivalent(left, right); difference == nil || difference.Right != undefinedOutput {
+		t.Errorf("difference is %v", difference)
+	}
+}
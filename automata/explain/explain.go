@@ -0,0 +1,372 @@
+// Package explain records the partition refinement behind the minimization of
+// Moore, Mealy and finite automata: the 0-, 1-, …, k-equivalence partitions
+// and the input that separates the states of every class that splits. It
+// records the subset construction of determinization and the reduction of
+// incompletely specified Mealy machines by compatible states as well. All of
+// them can be written as Markdown or HTML tables.
+package explain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// Explanation is the refinement of one machine. States, inputs and classes
+// are indexes into States, Alphabet and Step.Classes.
+type Explanation struct {
+	Kind     string
+	States   []string
+	Alphabet automata.Alphabet
+	Start    int
+	// Outputs holds the output of every state of a Moore machine and is nil
+	// for Mealy machines.
+	Outputs []string
+	// Next[state][symbol] is the target of a transition or NoState, and
+	// Emitted[state][symbol] its output for Mealy machines.
+	Next    [][]int
+	Emitted [][]string
+
+	Unreachable []int
+	Steps       []Step
+}
+
+// Step is the k-equivalence partition of the reachable states. The last step
+// is stable: refining it once more changes nothing.
+type Step struct {
+	K       int
+	Classes [][]int
+	// Class[state] is the class of a state or NoState when it is unreachable.
+	Class  []int
+	Splits []Split
+}
+
+// Split tells how a class of the previous step fell apart into several
+// classes of this one. Inputs[i] separates the first state of Parts[i+1]
+// from the first state of Parts[0]: their successors by it lie in different
+// classes of the previous step.
+type Split struct {
+	From   int
+	Parts  []int
+	Inputs []int
+}
+
+// Moore explains the minimization of a Moore machine, which starts with the
+// 0-equivalence of states with equal outputs.
+func Moore(m *automata.Moore) *Explanation {
+	e := &Explanation{
+		Kind:     "moore",
+		States:   m.States,
+		Alphabet: m.Alphabet,
+		Start:    m.Start,
+		Outputs:  m.Outputs,
+		Next:     m.Transitions,
+	}
+	e.refine(0, m.Outputs)
+
+	return e
+}
+
+// DFA explains the minimization of a DFA as that of a Moore machine with
+// the output F on final states. A partial DFA is completed with a trap state
+// first, as automata.MinimizeDFA does, so that a missing transition and one
+// into a dead state lead to the same class.
+func DFA(d *automata.DFA) *Explanation {
+	e := Moore(d.Complete(automata.TrapState).ToMoore("F", ""))
+	e.Kind = "dfa"
+
+	return e
+}
+
+// Mealy explains the minimization of a Mealy machine. Its states are first
+// told apart by the outputs of single inputs, so the steps start at the
+// 1-equivalence.
+func Mealy(m *automata.Mealy) *Explanation {
+	e := &Explanation{
+		Kind:     "mealy",
+		States:   m.States,
+		Alphabet: m.Alphabet,
+		Start:    m.Start,
+		Next:     make([][]int, len(m.States)),
+		Emitted:  make([][]string, len(m.States)),
+	}
+
+	keys := make([]string, len(m.States))
+	for state, row := range m.Transitions {
+		e.Next[state] = make([]int, len(row))
+		e.Emitted[state] = make([]string, len(row))
+
+		var key strings.Builder
+		for symbol, transition := range row {
+			e.Next[state][symbol] = transition.Target
+			e.Emitted[state][symbol] = transition.Output
+			if transition.Target == automata.NoState {
+				key.WriteString("-\x00")
+			} else {
+				key.WriteString(transition.Output + "/\x00")
+			}
+		}
+		keys[state] = key.String()
+	}
+	e.refine(1, keys)
+
+	return e
+}
+
+// Stable returns the last, coarsest stable partition.
+func (e *Explanation) Stable() Step {
+	return e.Steps[len(e.Steps)-1]
+}
+
+func (e *Explanation) refine(k int, keys []string) {
+	reachable := e.reachable()
+	order := make([]int, 0, len(e.States))
+	for _, state := range automata.StartFirst(len(e.States), e.Start) {
+		if reachable[state] {
+			order = append(order, state)
+		} else {
+			e.Unreachable = append(e.Unreachable, state)
+		}
+	}
+
+	step := e.newStep(k, order, func(state int) string {
+		return keys[state]
+	})
+	for {
+		next := e.newStep(step.K+1, order, func(state int) string {
+			var key strings.Builder
+			key.WriteString(strconv.Itoa(step.Class[state]))
+			for _, target := range e.Next[state] {
+				key.WriteByte(',')
+				key.WriteString(strconv.Itoa(step.classOf(target)))
+			}
+			return key.String()
+		})
+		e.Steps = append(e.Steps, step)
+		if len(next.Classes) == len(step.Classes) {
+			return
+		}
+
+		next.Splits = e.splits(step, next)
+		step = next
+	}
+}
+
+// newStep numbers the classes in the order their first states appear, so
+// the class of the start state is always the first one.
+func (e *Explanation) newStep(k int, order []int, key func(state int) string) Step {
+	step := Step{K: k, Class: make([]int, len(e.States))}
+	for state := range step.Class {
+		step.Class[state] = automata.NoState
+	}
+
+	indexes := make(map[string]int)
+	for _, state := range order {
+		name := key(state)
+		class, ok := indexes[name]
+		if !ok {
+			class = len(step.Classes)
+			indexes[name] = class
+			step.Classes = append(step.Classes, nil)
+		}
+		step.Class[state] = class
+		step.Classes[class] = append(step.Classes[class], state)
+	}
+
+	return step
+}
+
+func (e *Explanation) splits(previous, step Step) []Split {
+	var splits []Split
+	for from, states := range previous.Classes {
+		split := Split{From: from}
+		for _, state := range states {
+			part := step.Class[state]
+			if len(split.Parts) == 0 || split.Parts[len(split.Parts)-1] < part {
+				split.Parts = append(split.Parts, part)
+			}
+		}
+		if len(split.Parts) < 2 {
+			continue
+		}
+
+		first := step.Classes[split.Parts[0]][0]
+		for _, part := range split.Parts[1:] {
+			state := step.Classes[part][0]
+			split.Inputs = append(split.Inputs, e.separatingInput(previous, first, state))
+		}
+		splits = append(splits, split)
+	}
+
+	return splits
+}
+
+func (e *Explanation) separatingInput(previous Step, first, second int) int {
+	for symbol := range e.Alphabet {
+		if previous.classOf(e.Next[first][symbol]) != previous.classOf(e.Next[second][symbol]) {
+			return symbol
+		}
+	}
+
+	return automata.NoState
+}
+
+func (s Step) classOf(state int) int {
+	if state == automata.NoState {
+		return automata.NoState
+	}
+	return s.Class[state]
+}
+
+func (e *Explanation) reachable() []bool {
+	reachable := make([]bool, len(e.States))
+	reachable[e.Start] = true
+	queue := []int{e.Start}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, target := range e.Next[state] {
+			if target != automata.NoState && !reachable[target] {
+				reachable[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	return reachable
+}
+
+func (e *Explanation) title() string {
+	return fmt.Sprintf("Minimization of the %s machine", e.Kind)
+}
+
+func (e *Explanation) sections() []section {
+	intro := section{}
+	if len(e.Unreachable) > 0 {
+		intro.paragraphs = append(intro.paragraphs, fmt.Sprintf("Unreachable states are dropped first: %s.", e.stateList(e.Unreachable)))
+	}
+	rule := "the same output"
+	if e.Outputs == nil {
+		rule = "the same output for every input"
+	}
+	intro.paragraphs = append(intro.paragraphs, fmt.Sprintf(
+		"States are %d-equivalent when they have %s, and (k+1)-equivalent when they are k-equivalent and every input leads them to k-equivalent states.",
+		e.Steps[0].K, rule,
+	))
+	intro.paragraphs = append(intro.paragraphs, "A cell shows the target of a transition and the class of the target in the partition.")
+	sections := []section{intro}
+
+	for i, step := range e.Steps {
+		s := section{heading: fmt.Sprintf("%d-equivalence: %d classes", step.K, len(step.Classes))}
+		if i > 0 {
+			previous := e.Steps[i-1]
+			for _, split := range step.Splits {
+				s.items = append(s.items, e.splitText(previous, step, split))
+			}
+		}
+		s.table = e.stepTable(step)
+		sections = append(sections, s)
+	}
+
+	stable := e.Stable()
+	result := section{heading: "Result"}
+	result.paragraphs = append(result.paragraphs, fmt.Sprintf(
+		"Refining the %d-equivalence changes nothing, so it is the equivalence of states and the minimal machine has %d states.",
+		stable.K, len(stable.Classes),
+	))
+	for class, states := range stable.Classes {
+		if len(states) > 1 {
+			result.items = append(result.items, fmt.Sprintf("%s merges %s", className(class), e.stateList(states)))
+		}
+	}
+	if len(result.items) == 0 {
+		result.paragraphs = append(result.paragraphs, "No states are merged.")
+	}
+
+	return append(sections, result)
+}
+
+func (e *Explanation) stepTable(step Step) [][]string {
+	header := []string{"Class", "State"}
+	if e.Outputs != nil {
+		header = append(header, "Output")
+	}
+	header = append(header, e.Alphabet...)
+	rows := [][]string{header}
+
+	for class, states := range step.Classes {
+		for _, state := range states {
+			row := []string{className(class), e.States[state]}
+			if e.Outputs != nil {
+				row = append(row, e.Outputs[state])
+			}
+			for symbol := range e.Alphabet {
+				row = append(row, e.cell(step, state, symbol))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+func (e *Explanation) cell(step Step, state, symbol int) string {
+	target := e.Next[state][symbol]
+	if target == automata.NoState {
+		return "-"
+	}
+
+	text := e.States[target]
+	if e.Emitted != nil {
+		text += "/" + e.Emitted[state][symbol]
+	}
+
+	return fmt.Sprintf("%s (%s)", text, className(step.Class[target]))
+}
+
+func (e *Explanation) splitText(previous, step Step, split Split) string {
+	parts := make([]string, len(split.Parts))
+	for i, part := range split.Parts {
+		parts[i] = fmt.Sprintf("%s %s", className(part), e.stateList(step.Classes[part]))
+	}
+
+	first := step.Classes[split.Parts[0]][0]
+	reasons := make([]string, len(split.Inputs))
+	for i, symbol := range split.Inputs {
+		state := step.Classes[split.Parts[i+1]][0]
+		reasons[i] = fmt.Sprintf(
+			"%s leads %s to %s and %s to %s",
+			e.Alphabet[symbol],
+			e.States[first], e.targetText(previous, first, symbol),
+			e.States[state], e.targetText(previous, state, symbol),
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s %s of the %d-equivalence splits into %s: %s of the %d-equivalence.",
+		className(split.From), e.stateList(previous.Classes[split.From]), previous.K,
+		strings.Join(parts, " and "), strings.Join(reasons, "; "), previous.K,
+	)
+}
+
+func (e *Explanation) targetText(step Step, state, symbol int) string {
+	target := e.Next[state][symbol]
+	if target == automata.NoState {
+		return "nowhere"
+	}
+	return className(step.Class[target])
+}
+
+func (e *Explanation) stateList(states []int) string {
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = e.States[state]
+	}
+	return "{" + strings.Join(names, ", ") + "}"
+}
+
+func className(class int) string {
+	return fmt.Sprintf("C%d", class+1)
+}
@@ -0,0 +1,137 @@
+package explain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// Reduction records the state reduction of an incompletely specified Mealy
+// machine by automata.ReduceMealy: the implication table of its states, the
+// maximal compatibles and the closed cover the reduced machine is built from.
+type Reduction struct {
+	Mealy      *automata.Mealy
+	Result     *automata.Reduction
+	Compatible [][]bool
+	// States are the reachable states in table order, the rows and columns
+	// of the implication table.
+	States []int
+}
+
+// Reduce runs automata.ReduceMealy with the given search limit and keeps the
+// implication table it starts from.
+func Reduce(m *automata.Mealy, limit int) *Reduction {
+	r := &Reduction{
+		Mealy:      m,
+		Result:     automata.ReduceMealy(m, limit),
+		Compatible: automata.CompatiblePairs(m),
+	}
+
+	reachable := make([]bool, len(m.States))
+	for _, class := range r.Result.Compatibles {
+		for _, state := range class {
+			reachable[state] = true
+		}
+	}
+	for _, state := range automata.StartFirst(len(m.States), m.Start) {
+		if reachable[state] {
+			r.States = append(r.States, state)
+		}
+	}
+
+	return r
+}
+
+func (r *Reduction) title() string {
+	return "Reduction of the incompletely specified mealy machine"
+}
+
+func (r *Reduction) sections() []section {
+	implication := section{heading: "Implication table"}
+	implication.paragraphs = append(implication.paragraphs,
+		"Two states are compatible when no input word both of them specify gets different outputs from them; a missing next state or a - output may be anything.",
+		"A cell holds × for incompatible states, and otherwise the pairs of next states that must be compatible as well, ✓ when there are none.",
+	)
+	header := []string{""}
+	for _, state := range r.States[:len(r.States)-1] {
+		header = append(header, r.Mealy.States[state])
+	}
+	implication.table = [][]string{header}
+	for i, p := range r.States[1:] {
+		row := []string{r.Mealy.States[p]}
+		for j, q := range r.States[:len(r.States)-1] {
+			if j <= i {
+				row = append(row, r.pairText(p, q))
+			} else {
+				row = append(row, "")
+			}
+		}
+		implication.table = append(implication.table, row)
+	}
+	if len(r.States) == 1 {
+		implication.table = nil
+		implication.paragraphs = append(implication.paragraphs, "The machine has a single reachable state.")
+	}
+
+	maximal := section{heading: "Maximal compatibles"}
+	maximal.paragraphs = append(maximal.paragraphs, "States that are compatible in pairs are compatible together; these are the largest such sets.")
+	for _, class := range r.Result.Compatibles {
+		maximal.items = append(maximal.items, r.stateList(class))
+	}
+
+	cover := section{heading: fmt.Sprintf("Closed cover: %d classes", len(r.Result.Cover))}
+	cover.paragraphs = append(cover.paragraphs, "Every reachable state is in a class, and the next states of a class by each input lie within a class, so every class is a state of the reduced machine.")
+	cover.table = [][]string{{"State", "Class", "Implied classes"}}
+	for state, class := range r.Result.Cover {
+		var implied []string
+		for _, next := range automata.ImpliedClasses(r.Mealy, class) {
+			implied = append(implied, r.stateList(next))
+		}
+		cover.table = append(cover.table, []string{r.Result.Machine.States[state], r.stateList(class), strings.Join(implied, ", ")})
+	}
+	result := section{heading: "Result"}
+	text := fmt.Sprintf("The reduced machine has %d states, and no closed cover has fewer classes.", len(r.Result.Cover))
+	if !r.Result.Exact {
+		text = fmt.Sprintf("The reduced machine has %d states. The search for a smaller closed cover stopped at its limit, so one may exist.", len(r.Result.Cover))
+	}
+	result.paragraphs = append(result.paragraphs, text)
+
+	return []section{implication, maximal, cover, result}
+}
+
+// pairText is the implication table cell of p and q.
+func (r *Reduction) pairText(p, q int) string {
+	if !r.Compatible[p][q] {
+		return "×"
+	}
+
+	var pairs []string
+	for symbol := range r.Mealy.Alphabet {
+		left, right := r.Mealy.Transitions[p][symbol].Target, r.Mealy.Transitions[q][symbol].Target
+		if left == automata.NoState || right == automata.NoState || left == right {
+			continue
+		}
+		pair := r.Mealy.States[left] + "-" + r.Mealy.States[right]
+		if slices.Index(r.States, left) > slices.Index(r.States, right) {
+			pair = r.Mealy.States[right] + "-" + r.Mealy.States[left]
+		}
+		if !slices.Contains(pairs, pair) {
+			pairs = append(pairs, pair)
+		}
+	}
+	if len(pairs) == 0 {
+		return "✓"
+	}
+
+	return strings.Join(pairs, ", ")
+}
+
+func (r *Reduction) stateList(states []int) string {
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = r.Mealy.States[state]
+	}
+	return "{" + strings.Join(names, ", ") + "}"
+}
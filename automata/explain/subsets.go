@@ -0,0 +1,98 @@
+package explain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// Subsets records the subset construction of automata.DeterminizeSteps: the
+// ε-closure of every NFA state and the NFA states behind every DFA state Si,
+// in the order the worklist discovers them.
+type Subsets struct {
+	NFA *automata.NFA
+	*automata.Determinization
+}
+
+// Determinize runs automata.DeterminizeSteps and explains it; the DFA is
+// Subsets.DFA, so callers need not determinize the NFA again.
+func Determinize(nfa *automata.NFA) *Subsets {
+	return &Subsets{NFA: nfa, Determinization: automata.DeterminizeSteps(nfa)}
+}
+
+func (s *Subsets) title() string {
+	return "Subset construction"
+}
+
+func (s *Subsets) sections() []section {
+	closures := section{heading: "ε-closures"}
+	closures.paragraphs = append(closures.paragraphs, "The ε-closure of a state holds the states reachable from it by ε-transitions alone, itself included.")
+	closures.table = [][]string{{"NFA state", "Final", "ε-closure"}}
+	for _, state := range automata.StartFirst(len(s.NFA.States), s.NFA.Start) {
+		closures.table = append(closures.table, []string{
+			s.NFA.States[state], finalMark(s.NFA.Finals[state]), s.NFA.SubsetName(s.Closures[state]),
+		})
+	}
+
+	worklist := section{heading: "Worklist"}
+	worklist.paragraphs = append(worklist.paragraphs, fmt.Sprintf(
+		"The worklist starts with the ε-closure of %s. Every state taken from it moves by each input, and the ε-closure of the states moved to is a DFA state, added to the worklist when it is new.",
+		s.NFA.States[s.NFA.Start],
+	))
+	worklist.paragraphs = append(worklist.paragraphs, "A cell shows the states moved to and the DFA state of their ε-closure.")
+	header := []string{"Order", "DFA state", "NFA states", "Final", "Discovered"}
+	worklist.table = [][]string{append(header, s.NFA.Alphabet...)}
+	for current, subset := range s.Subsets {
+		row := []string{
+			strconv.Itoa(current + 1),
+			s.DFA.States[current],
+			s.NFA.SubsetName(subset),
+			finalMark(s.DFA.Finals[current]),
+			s.sourceText(current),
+		}
+		for symbol := range s.NFA.Alphabet {
+			row = append(row, s.moveText(current, symbol))
+		}
+		worklist.table = append(worklist.table, row)
+	}
+
+	result := section{heading: "Result"}
+	var finals []string
+	for state, final := range s.DFA.Finals {
+		if final {
+			finals = append(finals, s.DFA.States[state])
+		}
+	}
+	text := fmt.Sprintf("The DFA has %d states and none of them is final.", len(s.DFA.States))
+	if len(finals) > 0 {
+		text = fmt.Sprintf("The DFA has %d states, %d of them final: %s.", len(s.DFA.States), len(finals), strings.Join(finals, ", "))
+	}
+	result.paragraphs = append(result.paragraphs, text+" A DFA state is final when one of its NFA states is.")
+
+	return []section{closures, worklist, result}
+}
+
+func (s *Subsets) sourceText(state int) string {
+	source := s.Sources[state]
+	if source.From == automata.NoState {
+		return "start"
+	}
+	return fmt.Sprintf("%s by %s", s.DFA.States[source.From], s.NFA.Alphabet[source.Symbol])
+}
+
+func (s *Subsets) moveText(state, symbol int) string {
+	target := s.DFA.Transitions[state][symbol]
+	if target == automata.NoState {
+		return "-"
+	}
+	return fmt.Sprintf("%s → %s", s.NFA.SubsetName(s.Moves[state][symbol]), s.DFA.States[target])
+}
+
+func finalMark(final bool) string {
+	if final {
+		return "F"
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package explain
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	}
+
+	return "", fmt.Errorf("unknown explanation format %q", name)
+}
+
+// FormatOf picks the format by the extension of a file, Markdown unless it is
+// .html or .htm.
+func FormatOf(filePath string) Format {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".html", ".htm":
+		return FormatHTML
+	}
+
+	return FormatMarkdown
+}
+
+// Report is an explanation that can be written as a document: an
+// Explanation of minimization or a Subsets of determinization.
+type Report interface {
+	title() string
+	sections() []section
+}
+
+func Write(w io.Writer, r Report, format Format) error {
+	switch format {
+	case FormatMarkdown:
+		return WriteMarkdown(w, r)
+	case FormatHTML:
+		return WriteHTML(w, r)
+	}
+
+	return fmt.Errorf("unknown explanation format %q", format)
+}
+
+// section is a heading followed by paragraphs, a table whose first row is the
+// header and a list, any of them possibly empty. Both formats are written from
+// the same sections.
+type section struct {
+	heading    string
+	paragraphs []string
+	table      [][]string
+	items      []string
+}
+
+func WriteMarkdown(w io.Writer, r Report) error {
+	writer := bufio.NewWriter(w)
+
+	fmt.Fprintf(writer, "# %s\n", r.title())
+	for _, s := range r.sections() {
+		if s.heading != "" {
+			fmt.Fprintf(writer, "\n## %s\n", s.heading)
+		}
+		for _, paragraph := range s.paragraphs {
+			fmt.Fprintf(writer, "\n%s\n", paragraph)
+		}
+		for i, row := range s.table {
+			if i == 0 {
+				fmt.Fprintln(writer)
+			}
+			cells := make([]string, len(row))
+			for j, cell := range row {
+				cells[j] = markdownCell(cell)
+			}
+			fmt.Fprintf(writer, "| %s |\n", strings.Join(cells, " | "))
+			if i == 0 {
+				fmt.Fprintf(writer, "|%s\n", strings.Repeat(" --- |", len(row)))
+			}
+		}
+		for i, item := range s.items {
+			if i == 0 {
+				fmt.Fprintln(writer)
+			}
+			fmt.Fprintf(writer, "- %s\n", item)
+		}
+	}
+
+	return writer.Flush()
+}
+
+// markdownCell escapes the characters that would end a cell or a line.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// WriteHTML writes a standalone page that opens in any browser.
+func WriteHTML(w io.Writer, r Report) error {
+	writer := bufio.NewWriter(w)
+	title := html.EscapeString(r.title())
+
+	fmt.Fprintln(writer, "<!DOCTYPE html>")
+	fmt.Fprintln(writer, `<html lang="en">`)
+	fmt.Fprintf(writer, "<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", title)
+	fmt.Fprintln(writer, "<style>table { border-collapse: collapse; } th, td { border: 1px solid #999; padding: 2px 8px; }</style>")
+	fmt.Fprintln(writer, "</head>")
+	fmt.Fprintf(writer, "<body>\n<h1>%s</h1>\n", title)
+	for _, s := range r.sections() {
+		if s.heading != "" {
+			fmt.Fprintf(writer, "<h2>%s</h2>\n", html.EscapeString(s.heading))
+		}
+		for _, paragraph := range s.paragraphs {
+			fmt.Fprintf(writer, "<p>%s</p>\n", html.EscapeString(paragraph))
+		}
+		if len(s.table) > 0 {
+			fmt.Fprintln(writer, "<table>")
+			for i, row := range s.table {
+				tag := "td"
+				if i == 0 {
+					tag = "th"
+				}
+				fmt.Fprint(writer, "<tr>")
+				for _, cell := range row {
+					fmt.Fprintf(writer, "<%s>%s</%s>", tag, html.EscapeString(cell), tag)
+				}
+				fmt.Fprintln(writer, "</tr>")
+			}
+			fmt.Fprintln(writer, "</table>")
+		}
+		if len(s.items) > 0 {
+			fmt.Fprintln(writer, "<ul>")
+			for _, item := range s.items {
+				fmt.Fprintf(writer, "<li>%s</li>\n", html.EscapeString(item))
+			}
+			fmt.Fprintln(writer, "</ul>")
+		}
+	}
+	fmt.Fprintln(writer, "</body>\n</html>")
+
+	return writer.Flush()
+}
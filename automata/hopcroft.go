@@ -0,0 +1,221 @@
+package automata
+
+import "fmt"
+
+type Algorithm int
+
+const (
+	Refinement Algorithm = iota
+	Hopcroft
+	// Brzozowski minimizes acceptors by determinizing the reversal twice, see
+	// MinimizeBrzozowski. It has no partition step of its own, so Moore and
+	// Mealy machines are minimized by refinement instead.
+	Brzozowski
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case Refinement:
+		return "refinement"
+	case Hopcroft:
+		return "hopcroft"
+	case Brzozowski:
+		return "brzozowski"
+	}
+
+	return fmt.Sprintf("Algorithm(%d)", int(a))
+}
+
+func ParseAlgorithm(name string) (Algorithm, error) {
+	for _, algorithm := range []Algorithm{Refinement, Hopcroft, Brzozowski} {
+		if algorithm.String() == name {
+			return algorithm, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown minimization algorithm %q", name)
+}
+
+func (a Algorithm) partition(transitions [][]int, reachable []bool, classes []int) []int {
+	if a == Hopcroft {
+		return hopcroftPartition(transitions, reachable, classes)
+	}
+
+	return refinePartition(transitions, reachable, classes)
+}
+
+type blockPartition struct {
+	elements []int
+	location []int
+	blockOf  []int
+	first    []int
+	last     []int
+	marked   []int
+}
+
+func (p *blockPartition) size(block int) int {
+	return p.last[block] - p.first[block]
+}
+
+func (p *blockPartition) addBlock(first, last int) int {
+	p.first = append(p.first, first)
+	p.last = append(p.last, last)
+	p.marked = append(p.marked, 0)
+	block := len(p.first) - 1
+	for i := first; i < last; i++ {
+		p.blockOf[p.elements[i]] = block
+	}
+
+	return block
+}
+
+// mark moves state to the marked prefix of its block.
+func (p *blockPartition) mark(state int) {
+	block := p.blockOf[state]
+	i := p.location[state]
+	j := p.first[block] + p.marked[block]
+	p.elements[i], p.elements[j] = p.elements[j], p.elements[i]
+	p.location[p.elements[i]] = i
+	p.location[p.elements[j]] = j
+	p.marked[block]++
+}
+
+// hopcroftPartition computes the same coarsest partition as refinePartition
+// in O(k·n·log n). Missing transitions lead to an extra sink state that stays
+// in a block of its own, which matches how refinePartition treats them.
+func hopcroftPartition(transitions [][]int, reachable []bool, classes []int) []int {
+	statesNum := len(transitions)
+	sink := statesNum
+	symbolsNum := 0
+	if statesNum > 0 {
+		symbolsNum = len(transitions[0])
+	}
+
+	next := func(state, symbol int) int {
+		if state == sink || transitions[state][symbol] == NoState {
+			return sink
+		}
+		return transitions[state][symbol]
+	}
+
+	predecessors := make([][][]int, symbolsNum)
+	for symbol := range predecessors {
+		predecessors[symbol] = make([][]int, statesNum+1)
+		for state := 0; state <= statesNum; state++ {
+			if state != sink && !reachable[state] {
+				continue
+			}
+			target := next(state, symbol)
+			predecessors[symbol][target] = append(predecessors[symbol][target], state)
+		}
+	}
+
+	p := &blockPartition{
+		location: make([]int, statesNum+1),
+		blockOf:  make([]int, statesNum+1),
+	}
+	initial := make(map[int][]int)
+	var initialOrder []int
+	for state := 0; state < statesNum; state++ {
+		if !reachable[state] {
+			p.blockOf[state] = NoState
+			continue
+		}
+		if _, ok := initial[classes[state]]; !ok {
+			initialOrder = append(initialOrder, classes[state])
+		}
+		initial[classes[state]] = append(initial[classes[state]], state)
+	}
+	groups := make([][]int, 0, len(initialOrder)+1)
+	for _, class := range initialOrder {
+		groups = append(groups, initial[class])
+	}
+	groups = append(groups, []int{sink})
+	for _, group := range groups {
+		first := len(p.elements)
+		for _, state := range group {
+			p.location[state] = len(p.elements)
+			p.elements = append(p.elements, state)
+		}
+		p.addBlock(first, len(p.elements))
+	}
+
+	type splitter struct {
+		block  int
+		symbol int
+	}
+	var work []splitter
+	var inWork [][]bool
+	addWork := func(block, symbol int) {
+		for len(inWork) <= block {
+			inWork = append(inWork, make([]bool, symbolsNum))
+		}
+		if !inWork[block][symbol] {
+			inWork[block][symbol] = true
+			work = append(work, splitter{block, symbol})
+		}
+	}
+
+	largest := 0
+	for block := range p.first {
+		if p.size(block) > p.size(largest) {
+			largest = block
+		}
+	}
+	for block := range p.first {
+		if block == largest {
+			continue
+		}
+		for symbol := 0; symbol < symbolsNum; symbol++ {
+			addWork(block, symbol)
+		}
+	}
+
+	var splitterStates, touched []int
+	for len(work) > 0 {
+		s := work[len(work)-1]
+		work = work[:len(work)-1]
+		inWork[s.block][s.symbol] = false
+
+		splitterStates = append(splitterStates[:0], p.elements[p.first[s.block]:p.last[s.block]]...)
+		touched = touched[:0]
+		for _, target := range splitterStates {
+			for _, state := range predecessors[s.symbol][target] {
+				block := p.blockOf[state]
+				if p.marked[block] == 0 {
+					touched = append(touched, block)
+				}
+				p.mark(state)
+			}
+		}
+
+		for _, block := range touched {
+			marked := p.marked[block]
+			p.marked[block] = 0
+			if marked == p.size(block) {
+				continue
+			}
+
+			split := p.addBlock(p.first[block], p.first[block]+marked)
+			p.first[block] += marked
+			for symbol := 0; symbol < symbolsNum; symbol++ {
+				if block < len(inWork) && inWork[block][symbol] || p.size(split) <= p.size(block) {
+					addWork(split, symbol)
+				} else {
+					addWork(block, symbol)
+				}
+			}
+		}
+	}
+
+	result := make([]int, statesNum)
+	for state := range result {
+		if reachable[state] {
+			result[state] = p.blockOf[state]
+		} else {
+			result[state] = NoState
+		}
+	}
+
+	return result
+}
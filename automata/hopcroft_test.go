@@ -0,0 +1,87 @@
+package automata
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestHopcroftMatchesRefinementMoore(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		m := randomMoore(rng, 1+rng.Intn(30), 1+rng.Intn(3), 1+rng.Intn(3))
+		if i%2 == 1 {
+			withHoles(rng, m.Transitions)
+		}
+
+		refinement, hopcroft := MinimizeMooreWith(m, Refinement), MinimizeMooreWith(m, Hopcroft)
+		if !reflect.DeepEqual(refinement, hopcroft) {
+			t.Fatalf("machine %d: hopcroft gives\n%v\nrefinement gives\n%v", i, hopcroft, refinement)
+		}
+	}
+}
+
+func TestHopcroftMatchesRefinementDFA(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		d := randomDFA(rng, 1+rng.Intn(30), 1+rng.Intn(3))
+		if i%2 == 1 {
+			withHoles(rng, d.Transitions)
+		}
+
+		refinement, hopcroft := MinimizeDFAWith(d, Refinement), MinimizeDFAWith(d, Hopcroft)
+		if !reflect.DeepEqual(refinement, hopcroft) {
+			t.Fatalf("DFA %d: hopcroft gives\n%v\nrefinement gives\n%v", i, hopcroft, refinement)
+		}
+	}
+}
+
+func TestHopcroftMatchesRefinementMealy(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 200; i++ {
+		m := randomMealy(rng, 1+rng.Intn(30), 1+rng.Intn(3), 1+rng.Intn(3))
+		if i%2 == 1 {
+			withMealyHoles(rng, m)
+		}
+
+		refinement, hopcroft := MinimizeMealyWith(m, Refinement), MinimizeMealyWith(m, Hopcroft)
+		if !reflect.DeepEqual(refinement, hopcroft) {
+			t.Fatalf("machine %d: hopcroft gives\n%v\nrefinement gives\n%v", i, hopcroft, refinement)
+		}
+	}
+}
+
+const benchmarkStates = 10000
+
+func benchmarkMoore(b *testing.B, algorithm Algorithm) {
+	m := randomMoore(rand.New(rand.NewSource(1)), benchmarkStates, 4, 2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MinimizeMooreWith(m, algorithm)
+	}
+}
+
+func BenchmarkMinimizeMooreRefinement(b *testing.B) { benchmarkMoore(b, Refinement) }
+func BenchmarkMinimizeMooreHopcroft(b *testing.B)   { benchmarkMoore(b, Hopcroft) }
+
+func benchmarkDFA(b *testing.B, algorithm Algorithm) {
+	d := randomDFA(rand.New(rand.NewSource(1)), benchmarkStates, 4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MinimizeDFAWith(d, algorithm)
+	}
+}
+
+func BenchmarkMinimizeDFARefinement(b *testing.B) { benchmarkDFA(b, Refinement) }
+func BenchmarkMinimizeDFAHopcroft(b *testing.B)   { benchmarkDFA(b, Hopcroft) }
+
+func benchmarkMealy(b *testing.B, algorithm Algorithm) {
+	m := randomMealy(rand.New(rand.NewSource(1)), benchmarkStates, 4, 2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MinimizeMealyWith(m, algorithm)
+	}
+}
+
+func BenchmarkMinimizeMealyRefinement(b *testing.B) { benchmarkMealy(b, Refinement) }
+func BenchmarkMinimizeMealyHopcroft(b *testing.B)   { benchmarkMealy(b, Hopcroft) }
@@ -0,0 +1,266 @@
+// Package kiss reads and writes Mealy machines in the KISS2 format of the
+// MCNC/LGSynth FSM benchmarks:
+//
+//	.i 1
+//	.o 1
+//	.s 2
+//	.p 3
+//	.r st0
+//	0 st0 st0 0
+//	1 st0 st1 -
+//	- st1 st0 1
+//	.e
+//
+// Every line is an input cube, the present state, the next state and the
+// output cube; "-" in a cube is a don't-care.
+package kiss
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// MaxInputs bounds .i, as the machine gets one input symbol per minterm.
+const MaxInputs = 16
+
+// AnyState stands for every state in the present state column and for an
+// unspecified next state.
+const AnyState = "*"
+
+type line struct {
+	number  int
+	input   string
+	present string
+	next    string
+	output  string
+}
+
+type header struct {
+	inputs, outputs     int
+	states, products    int
+	reset               string
+	hasStates, hasTerms bool
+}
+
+// Read parses a KISS2 machine. Its alphabet is every input minterm in binary
+// order, so a cube with don't-cares sets the transition for each minterm it
+// covers. Next states written as * or - are left undefined.
+func Read(r io.Reader) (*automata.Mealy, error) {
+	h := header{inputs: -1, outputs: -1}
+	var lines []line
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(fields[0], ".") {
+			end, err := h.directive(fields, number)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				break
+			}
+			continue
+		}
+
+		if h.inputs < 0 || h.outputs < 0 {
+			return nil, fmt.Errorf("line %d: transition before .i and .o", number)
+		}
+		l, err := h.transition(fields, number)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return h.build(lines)
+}
+
+// ReadFile reads the KISS2 machine of a file, see Read.
+func ReadFile(filePath string) (*automata.Mealy, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+// directive applies a header line and reports whether it ends the machine.
+func (h *header) directive(fields []string, number int) (bool, error) {
+	switch fields[0] {
+	case ".e", ".end":
+		return true, nil
+	case ".r":
+		if len(fields) != 2 {
+			return false, fmt.Errorf("line %d: .r needs one state", number)
+		}
+		h.reset = fields[1]
+		return false, nil
+	case ".i", ".o", ".s", ".p":
+	default:
+		return false, fmt.Errorf("line %d: unknown directive %s", number, fields[0])
+	}
+
+	if len(fields) != 2 {
+		return false, fmt.Errorf("line %d: %s needs one number", number, fields[0])
+	}
+	value, err := strconv.Atoi(fields[1])
+	if err != nil || value < 0 {
+		return false, fmt.Errorf("line %d: %s needs a number, got %q", number, fields[0], fields[1])
+	}
+
+	switch fields[0] {
+	case ".i":
+		if value == 0 || value > MaxInputs {
+			return false, fmt.Errorf("line %d: .i must be from 1 to %d, got %d", number, MaxInputs, value)
+		}
+		h.inputs = value
+	case ".o":
+		h.outputs = value
+	case ".s":
+		h.states, h.hasStates = value, true
+	case ".p":
+		h.products, h.hasTerms = value, true
+	}
+	return false, nil
+}
+
+func (h *header) transition(fields []string, number int) (line, error) {
+	if h.outputs == 0 && len(fields) == 3 {
+		fields = append(fields, "")
+	}
+	if len(fields) != 4 {
+		return line{}, fmt.Errorf("line %d: want input, present state, next state and output, got %d fields", number, len(fields))
+	}
+
+	l := line{number: number, input: fields[0], present: fields[1], next: fields[2], output: fields[3]}
+	if err := checkCube(l.input, h.inputs); err != nil {
+		return line{}, fmt.Errorf("line %d: input %w", number, err)
+	}
+	if err := checkCube(l.output, h.outputs); err != nil {
+		return line{}, fmt.Errorf("line %d: output %w", number, err)
+	}
+	if l.next == "-" {
+		l.next = AnyState
+	}
+	return l, nil
+}
+
+func checkCube(cube string, width int) error {
+	if len(cube) != width {
+		return fmt.Errorf("%q must have %d bits", cube, width)
+	}
+	if i := strings.IndexFunc(cube, func(c rune) bool { return c != '0' && c != '1' && c != '-' }); i != -1 {
+		return fmt.Errorf("%q has %q, only 0, 1 and - are allowed", cube, cube[i])
+	}
+	return nil
+}
+
+func (h *header) build(lines []line) (*automata.Mealy, error) {
+	if h.inputs < 0 || h.outputs < 0 {
+		return nil, fmt.Errorf("machine needs .i and .o")
+	}
+	if h.hasTerms && h.products != len(lines) {
+		return nil, fmt.Errorf(".p is %d, but there are %d transitions", h.products, len(lines))
+	}
+
+	var states []string
+	addState := func(name string) {
+		if name != AnyState && !slices.Contains(states, name) {
+			states = append(states, name)
+		}
+	}
+	if h.reset != "" {
+		addState(h.reset)
+	}
+	for _, l := range lines {
+		addState(l.present)
+	}
+	for _, l := range lines {
+		addState(l.next)
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("machine has no states")
+	}
+	if h.hasStates && h.states != len(states) {
+		return nil, fmt.Errorf(".s is %d, but there are %d states", h.states, len(states))
+	}
+
+	alphabet := make(automata.Alphabet, 1<<h.inputs)
+	for i := range alphabet {
+		alphabet[i] = minterm(i, h.inputs)
+	}
+
+	m := automata.NewMealy(states, alphabet)
+	setBy := make([][]int, len(states))
+	for i := range setBy {
+		setBy[i] = make([]int, len(alphabet))
+	}
+
+	for _, l := range lines {
+		present := []int{m.StateIndex(l.present)}
+		if l.present == AnyState {
+			present = make([]int, len(states))
+			for i := range present {
+				present[i] = i
+			}
+		}
+		transition := automata.MealyTransition{Target: automata.NoState, Output: l.output}
+		if l.next != AnyState {
+			transition.Target = m.StateIndex(l.next)
+		}
+
+		for _, symbol := range covered(l.input) {
+			for _, state := range present {
+				if previous := setBy[state][symbol]; previous != 0 && m.Transitions[state][symbol] != transition {
+					return nil, fmt.Errorf("line %d: state %s on input %s conflicts with line %d",
+						l.number, states[state], alphabet[symbol], previous)
+				}
+				m.Transitions[state][symbol] = transition
+				setBy[state][symbol] = l.number
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func minterm(value, width int) string {
+	bits := strconv.FormatInt(int64(value), 2)
+	return strings.Repeat("0", width-len(bits)) + bits
+}
+
+// covered lists the minterms of a cube as numbers.
+func covered(cube string) []int {
+	minterms := []int{0}
+	for _, c := range cube {
+		next := make([]int, 0, 2*len(minterms))
+		for _, value := range minterms {
+			if c != '1' {
+				next = append(next, value<<1)
+			}
+			if c != '0' {
+				next = append(next, value<<1|1)
+			}
+		}
+		minterms = next
+	}
+	return minterms
+}
@@ -0,0 +1,190 @@
+package kiss
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// A small MCNC style machine: cubes with don't-cares, an unspecified next
+// state and a don't-care output.
+const machine = `# three states
+.i 2
+.o 1
+.s 3
+.p 6
+.r st0
+0- st0 st0 0
+11 st0 st1 0
+10 st0 st2 1
+-- st1 st0 1
+00 st2 * 1
+1- st2 st2 -
+.e
+`
+
+func read(t *testing.T, text string) *automata.Mealy {
+	t.Helper()
+	m, err := Read(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func write(t *testing.T, m *automata.Mealy) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestRead(t *testing.T) {
+	m := read(t, machine)
+
+	if !reflect.DeepEqual(m.States, []string{"st0", "st1", "st2"}) || m.Start != 0 {
+		t.Fatalf("states %v, start %d", m.States, m.Start)
+	}
+	if !reflect.DeepEqual([]string(m.Alphabet), []string{"00", "01", "10", "11"}) {
+		t.Fatalf("alphabet %v", m.Alphabet)
+	}
+
+	want := [][]automata.MealyTransition{
+		{{Target: 0, Output: "0"}, {Target: 0, Output: "0"}, {Target: 2, Output: "1"}, {Target: 1, Output: "0"}},
+		{{Target: 0, Output: "1"}, {Target: 0, Output: "1"}, {Target: 0, Output: "1"}, {Target: 0, Output: "1"}},
+		{{Target: automata.NoState, Output: "1"}, {Target: automata.NoState}, {Target: 2, Output: "-"}, {Target: 2, Output: "-"}},
+	}
+	if !reflect.DeepEqual(m.Transitions, want) {
+		t.Errorf("transitions %v, want %v", m.Transitions, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	m := read(t, machine)
+	text := write(t, m)
+
+	for _, line := range []string{".r st0", "0- st0 st0 0", "-- st1 st0 1", "00 st2 * 1", "1- st2 st2 -"} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("written machine has no %q:\n%s", line, text)
+		}
+	}
+
+	again := read(t, text)
+	if !reflect.DeepEqual(again, m) {
+		t.Errorf("read back %+v, want %+v", again, m)
+	}
+	if written := write(t, again); written != text {
+		t.Errorf("written again:\n%s\nwant:\n%s", written, text)
+	}
+}
+
+func TestUnspecifiedNextStates(t *testing.T) {
+	m := read(t, ".i 1\n.o 1\n0 st0 * 1\n1 st0 - 0\n.e\n")
+
+	want := []automata.MealyTransition{{Target: automata.NoState, Output: "1"}, {Target: automata.NoState, Output: "0"}}
+	if !reflect.DeepEqual(m.Transitions[0], want) {
+		t.Errorf("transitions %v, want %v", m.Transitions[0], want)
+	}
+	if text := write(t, m); !strings.Contains(text, "0 st0 * 1\n") || !strings.Contains(text, "1 st0 * 0\n") {
+		t.Errorf("written machine:\n%s", text)
+	}
+}
+
+func TestAnyPresentState(t *testing.T) {
+	m := read(t, ".i 1\n.o 1\n0 st0 st1 0\n1 * st0 1\n.e\n")
+
+	for state := range m.States {
+		if got := m.Transitions[state][1]; got != (automata.MealyTransition{Target: 0, Output: "1"}) {
+			t.Errorf("state %s on 1: %v", m.States[state], got)
+		}
+	}
+}
+
+func TestOverlappingCubes(t *testing.T) {
+	// Overlapping cubes may agree, but not conflict.
+	if _, err := Read(strings.NewReader(".i 2\n.o 1\n0- st0 st0 0\n00 st0 st0 0\n.e\n")); err != nil {
+		t.Errorf("agreeing cubes: %v", err)
+	}
+
+	_, err := Read(strings.NewReader(".i 2\n.o 1\n0- st0 st0 0\n00 st0 st1 0\n.e\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "conflicts with line 3") {
+		t.Errorf("conflicting cubes: %v", err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"no header", "0 st0 st0 0\n", "before .i and .o"},
+		{"wide input", ".i 1\n.o 1\n00 st0 st0 0\n", "must have 1 bits"},
+		{"bad output", ".i 1\n.o 1\n0 st0 st0 x\n", "only 0, 1 and - are allowed"},
+		{"products", ".i 1\n.o 1\n.p 2\n0 st0 st0 0\n", ".p is 2"},
+		{"states", ".i 1\n.o 1\n.s 1\n0 st0 st1 0\n", ".s is 1"},
+		{"directive", ".i 1\n.x\n", "unknown directive .x"},
+	}
+
+	for _, tt := range tests {
+		_, err := Read(strings.NewReader(tt.text))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestMergeCubes(t *testing.T) {
+	tests := []struct {
+		cubes, want []string
+	}{
+		{nil, []string{}},
+		{[]string{"000", "001", "010", "011"}, []string{"0--"}},
+		{[]string{"00", "01", "10", "11"}, []string{"--"}},
+		{[]string{"00", "11"}, []string{"00", "11"}},
+		{[]string{"01", "00", "10"}, []string{"-0", "01"}},
+	}
+
+	for _, tt := range tests {
+		got := mergeCubes(slices.Clone(tt.cubes))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mergeCubes(%v) = %v, want %v", tt.cubes, got, tt.want)
+		}
+
+		var minterms []int
+		for _, cube := range got {
+			minterms = append(minterms, covered(cube)...)
+		}
+		slices.Sort(minterms)
+		var want []int
+		for _, cube := range tt.cubes {
+			want = append(want, covered(cube)...)
+		}
+		slices.Sort(want)
+		if !slices.Equal(minterms, want) {
+			t.Errorf("mergeCubes(%v) covers %v, want %v once each", tt.cubes, minterms, want)
+		}
+	}
+}
+
+func TestWriteEncodesNames(t *testing.T) {
+	m := automata.NewMealy([]string{"a", "b"}, automata.Alphabet{"x", "y", "z"})
+	m.Transitions[0] = []automata.MealyTransition{{Target: 1, Output: "go"}, {Target: 0, Output: "stop"}, {Target: 0, Output: "stop"}}
+	m.Transitions[1] = []automata.MealyTransition{{Target: 0, Output: "go"}, {Target: 1, Output: "go"}, {Target: 1, Output: "go"}}
+
+	text := write(t, m)
+	for _, line := range []string{"# input x = 00", "# input z = 10", "# output go = 0", "# output stop = 1", ".i 2", ".o 1"} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("written machine has no %q:\n%s", line, text)
+		}
+	}
+
+	again := read(t, text)
+	if len(again.States) != 2 || again.Transitions[0][0] != (automata.MealyTransition{Target: 1, Output: "0"}) {
+		t.Errorf("read back %+v", again)
+	}
+}
@@ -0,0 +1,173 @@
+package kiss
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// Write emits the machine in KISS2, merging the inputs that lead to the same
+// transition into cubes. Input symbols that are not binary minterms of one
+// length, and outputs that are not binary cubes, are encoded by their index;
+// comments at the top list the codes.
+func Write(w io.Writer, m *automata.Mealy) error {
+	writer := bufio.NewWriter(w)
+
+	inputs := slices.Clone([]string(m.Alphabet))
+	if !sameWidthCubes(inputs, "01") {
+		inputs = encode(writer, "input", m.Alphabet)
+	}
+
+	var outputNames []string
+	for _, row := range m.Transitions {
+		for _, transition := range row {
+			if transition.HasOutput() && !slices.Contains(outputNames, transition.Output) {
+				outputNames = append(outputNames, transition.Output)
+			}
+		}
+	}
+	slices.Sort(outputNames)
+	outputs := make(map[string]string, len(outputNames))
+	codes := outputNames
+	if !sameWidthCubes(outputNames, "01-") {
+		codes = encode(writer, "output", outputNames)
+	}
+	for i, name := range outputNames {
+		outputs[name] = codes[i]
+	}
+	outputWidth := 1
+	if len(codes) > 0 {
+		outputWidth = len(codes[0])
+	}
+	outputs[automata.DontCare] = strings.Repeat("-", outputWidth)
+
+	type group struct {
+		transition automata.MealyTransition
+		cubes      []string
+	}
+	var lines []string
+	order := automata.StartFirst(len(m.States), m.Start)
+	for _, state := range order {
+		var groups []group
+		for symbol, transition := range m.Transitions[state] {
+			if transition.Target == automata.NoState && !transition.HasOutput() {
+				continue
+			}
+			i := slices.IndexFunc(groups, func(g group) bool { return g.transition == transition })
+			if i == -1 {
+				groups = append(groups, group{transition: transition})
+				i = len(groups) - 1
+			}
+			groups[i].cubes = append(groups[i].cubes, inputs[symbol])
+		}
+
+		for _, g := range groups {
+			next := AnyState
+			if g.transition.Target != automata.NoState {
+				next = m.States[g.transition.Target]
+			}
+			for _, cube := range mergeCubes(g.cubes) {
+				lines = append(lines, fmt.Sprintf("%s %s %s %s",
+					cube, m.States[state], next, outputs[g.transition.Output]))
+			}
+		}
+	}
+
+	inputWidth := 0
+	if len(inputs) > 0 {
+		inputWidth = len(inputs[0])
+	}
+	fmt.Fprintf(writer, ".i %d\n", inputWidth)
+	fmt.Fprintf(writer, ".o %d\n", outputWidth)
+	fmt.Fprintf(writer, ".s %d\n", len(m.States))
+	fmt.Fprintf(writer, ".p %d\n", len(lines))
+	fmt.Fprintf(writer, ".r %s\n", m.States[m.Start])
+	for _, l := range lines {
+		fmt.Fprintln(writer, strings.TrimRight(l, " "))
+	}
+	fmt.Fprintln(writer, ".e")
+
+	return writer.Flush()
+}
+
+// WriteFile writes the machine to a file in KISS2, see Write.
+func WriteFile(filePath string, m *automata.Mealy) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = Write(file, m); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func sameWidthCubes(values []string, chars string) bool {
+	for _, value := range values {
+		if len(value) != len(values[0]) || strings.Trim(value, chars) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// encode numbers the names in binary and lists the codes as comments.
+func encode(w io.Writer, kind string, names []string) []string {
+	width := max(bits.Len(uint(len(names)-1)), 1)
+	codes := make([]string, len(names))
+	for i, name := range names {
+		codes[i] = minterm(i, width)
+		fmt.Fprintf(w, "# %s %s = %s\n", kind, name, codes[i])
+	}
+	return codes
+}
+
+// mergeCubes joins pairs of cubes that differ in one bit until none are
+// left, in sorted order so the output is stable. The result covers the same
+// minterms and the cubes stay disjoint.
+func mergeCubes(cubes []string) []string {
+	set := make(map[string]bool, len(cubes))
+	for _, cube := range cubes {
+		set[cube] = true
+	}
+
+	for merged := true; merged; {
+		merged = false
+		for position := 0; len(cubes) > 0 && position < len(cubes[0]); position++ {
+			keys := make([]string, 0, len(set))
+			for cube := range set {
+				keys = append(keys, cube)
+			}
+			slices.Sort(keys)
+
+			for _, cube := range keys {
+				if cube[position] != '0' || !set[cube] {
+					continue
+				}
+				pair := cube[:position] + "1" + cube[position+1:]
+				if set[pair] {
+					delete(set, cube)
+					delete(set, pair)
+					set[cube[:position]+"-"+cube[position+1:]] = true
+					merged = true
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(set))
+	for cube := range set {
+		result = append(result, cube)
+	}
+	slices.Sort(result)
+	return result
+}
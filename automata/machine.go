@@ -0,0 +1,102 @@
+// Package automata holds the machine types shared by NFAToDFA, minimization
+// and mooreMealyConversion. States and input symbols are addressed by index,
+// names are kept only for reading and printing.
+//
+// The tools keep their own table representations and convert to and from
+// these types at the edges; tables are read only by package table, so every
+// tool parses a machine the same way.
+package automata
+
+import (
+	"fmt"
+	"slices"
+)
+
+const NoState = -1
+
+type Alphabet []string
+
+func (a Alphabet) Index(symbol string) int {
+	return slices.Index(a, symbol)
+}
+
+func stateIndex(states []string, name string) int {
+	return slices.Index(states, name)
+}
+
+func validateStates(states []string, alphabet Alphabet, start int) error {
+	if len(states) == 0 {
+		return fmt.Errorf("machine has no states")
+	}
+	seenStates := make(map[string]struct{}, len(states))
+	for _, state := range states {
+		if _, ok := seenStates[state]; ok {
+			return fmt.Errorf("duplicate state %q", state)
+		}
+		seenStates[state] = struct{}{}
+	}
+	seenSymbols := make(map[string]struct{}, len(alphabet))
+	for _, symbol := range alphabet {
+		if _, ok := seenSymbols[symbol]; ok {
+			return fmt.Errorf("duplicate input symbol %q", symbol)
+		}
+		seenSymbols[symbol] = struct{}{}
+	}
+	if start < 0 || start >= len(states) {
+		return fmt.Errorf("start state %d is out of range", start)
+	}
+
+	return nil
+}
+
+func validateTarget(states []string, alphabet Alphabet, from, symbol, target int) error {
+	if target == NoState {
+		return nil
+	}
+	if target < 0 || target >= len(states) {
+		return fmt.Errorf("transition %s --%s--> %d points to unknown state", states[from], alphabet[symbol], target)
+	}
+
+	return nil
+}
+
+func newTransitionRow(symbolsNum int) []int {
+	row := make([]int, symbolsNum)
+	for i := range row {
+		row[i] = NoState
+	}
+
+	return row
+}
+
+func newTransitionTable(statesNum, symbolsNum int) [][]int {
+	transitions := make([][]int, statesNum)
+	for i := range transitions {
+		transitions[i] = newTransitionRow(symbolsNum)
+	}
+
+	return transitions
+}
+
+func cloneTransitionTable(transitions [][]int) [][]int {
+	cloned := make([][]int, len(transitions))
+	for i := range transitions {
+		cloned[i] = slices.Clone(transitions[i])
+	}
+
+	return cloned
+}
+
+// StartFirst lists state indexes with the start state moved to the front,
+// the order every table format in this repository expects.
+func StartFirst(statesNum, start int) []int {
+	order := make([]int, 0, statesNum)
+	order = append(order, start)
+	for i := 0; i < statesNum; i++ {
+		if i != start {
+			order = append(order, i)
+		}
+	}
+
+	return order
+}
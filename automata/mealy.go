@@ -0,0 +1,143 @@
+package automata
+
+import (
+	"fmt"
+	"slices"
+)
+
+type MealyTransition struct {
+	Target int
+	Output string
+}
+
+type Mealy struct {
+	States      []string
+	Alphabet    Alphabet
+	Start       int
+	Transitions [][]MealyTransition
+}
+
+func NewMealy(states []string, alphabet Alphabet) *Mealy {
+	transitions := make([][]MealyTransition, len(states))
+	for i := range transitions {
+		transitions[i] = make([]MealyTransition, len(alphabet))
+		for j := range transitions[i] {
+			transitions[i][j] = MealyTransition{Target: NoState}
+		}
+	}
+
+	return &Mealy{
+		States:      slices.Clone(states),
+		Alphabet:    slices.Clone(alphabet),
+		Start:       0,
+		Transitions: transitions,
+	}
+}
+
+func (m *Mealy) StateIndex(name string) int {
+	return stateIndex(m.States, name)
+}
+
+func (m *Mealy) Next(state, symbol int) MealyTransition {
+	return m.Transitions[state][symbol]
+}
+
+func (m *Mealy) IsComplete() bool {
+	for _, row := range m.Transitions {
+		for _, transition := range row {
+			if transition.Target == NoState {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (m *Mealy) Validate() error {
+	if err := validateStates(m.States, m.Alphabet, m.Start); err != nil {
+		return err
+	}
+	if len(m.Transitions) != len(m.States) {
+		return fmt.Errorf("mealy machine has %d transition rows for %d states", len(m.Transitions), len(m.States))
+	}
+	for from, row := range m.Transitions {
+		if len(row) != len(m.Alphabet) {
+			return fmt.Errorf("state %s has %d transitions for %d input symbols", m.States[from], len(row), len(m.Alphabet))
+		}
+		for symbol, transition := range row {
+			if err := validateTarget(m.States, m.Alphabet, from, symbol, transition.Target); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Mealy) Clone() *Mealy {
+	transitions := make([][]MealyTransition, len(m.Transitions))
+	for i := range m.Transitions {
+		transitions[i] = slices.Clone(m.Transitions[i])
+	}
+
+	return &Mealy{
+		States:      slices.Clone(m.States),
+		Alphabet:    slices.Clone(m.Alphabet),
+		Start:       m.Start,
+		Transitions: transitions,
+	}
+}
+
+// ToMoore splits every state into one Moore state per distinct output on its
+// incoming transitions. States without incoming transitions keep an empty
+// output so that the start state is never lost.
+func (m *Mealy) ToMoore() *Moore {
+	type pair struct {
+		state  int
+		output string
+	}
+
+	outputsByState := make([][]string, len(m.States))
+	for _, row := range m.Transitions {
+		for _, transition := range row {
+			if transition.Target != NoState && !slices.Contains(outputsByState[transition.Target], transition.Output) {
+				outputsByState[transition.Target] = append(outputsByState[transition.Target], transition.Output)
+			}
+		}
+	}
+
+	pairs := make([]pair, 0, len(m.States))
+	pairIndexes := make(map[pair]int)
+	for state, outputs := range outputsByState {
+		if len(outputs) == 0 {
+			outputs = []string{""}
+		}
+		for _, output := range outputs {
+			pairIndexes[pair{state, output}] = len(pairs)
+			pairs = append(pairs, pair{state, output})
+		}
+	}
+
+	states := make([]string, len(pairs))
+	for i := range pairs {
+		states[i] = fmt.Sprintf("q%d", i)
+	}
+	moore := NewMoore(states, m.Alphabet)
+	for i, p := range pairs {
+		moore.Outputs[i] = p.output
+		for symbol, transition := range m.Transitions[p.state] {
+			if transition.Target != NoState {
+				moore.Transitions[i][symbol] = pairIndexes[pair{transition.Target, transition.Output}]
+			}
+		}
+	}
+	for i, p := range pairs {
+		if p.state == m.Start {
+			moore.Start = i
+			break
+		}
+	}
+
+	return moore
+}
@@ -0,0 +1,284 @@
+package automata
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MinimizeMoore drops unreachable states and merges equivalent ones. States of
+// the result are named q0, q1, … in breadth-first order from the start state.
+func MinimizeMoore(m *Moore) *Moore {
+	return MinimizeMooreWith(m, Refinement)
+}
+
+// MinimizeMooreWith is MinimizeMoore with a choice of partition algorithm.
+// Every algorithm yields the same machine, state names included.
+func MinimizeMooreWith(m *Moore, algorithm Algorithm) *Moore {
+	reachable := reachableStates(m.Transitions, m.Start)
+	classes := algorithm.partition(m.Transitions, reachable, outputClasses(m.Outputs, reachable))
+	order, next := quotient(m.Transitions, m.Start, classes)
+
+	minimized := NewMoore(quotientNames(len(order)), m.Alphabet)
+	for class, state := range order {
+		minimized.Outputs[class] = m.Outputs[state]
+		minimized.Transitions[class] = next[class]
+	}
+
+	return minimized
+}
+
+func MinimizeDFA(d *DFA) *DFA {
+	return MinimizeDFAWith(d, Refinement)
+}
+
+// MinimizeDFAWith is MinimizeDFA with a choice of algorithm, all of them
+// yielding the same machine, state names included. A missing transition leads
+// to the dead state, from which no final state can be reached, so the result
+// of a partial DFA has no dead state and that of a complete DFA is complete,
+// with one dead state at most.
+func MinimizeDFAWith(d *DFA, algorithm Algorithm) *DFA {
+	if algorithm == Brzozowski {
+		minimized := MinimizeBrzozowski(d.ToNFA())
+		if d.IsComplete() {
+			return withDeadState(minimized)
+		}
+		return minimized
+	}
+
+	minimized := MinimizeMooreWith(d.Complete(TrapState).ToMoore("F", ""), algorithm).ToDFA("F")
+	if d.IsComplete() {
+		return minimized
+	}
+
+	return withoutDeadState(minimized)
+}
+
+// withoutDeadState drops the transitions into states of d from which no final
+// state can be reached, and so those states. A dead start state stays, without
+// transitions, as the DFA of the empty language.
+func withoutDeadState(d *DFA) *DFA {
+	live := liveStates(d)
+	trimmed := d.Clone()
+	for _, row := range trimmed.Transitions {
+		for symbol, target := range row {
+			if target != NoState && !live[target] {
+				row[symbol] = NoState
+			}
+		}
+	}
+
+	return renumbered(trimmed)
+}
+
+// withDeadState completes a DFA without dead states. The missing transitions
+// lead to a new trap state, or to the start state when it is the dead state
+// of the empty language already.
+func withDeadState(d *DFA) *DFA {
+	if d.IsComplete() {
+		return d
+	}
+
+	hasTransition := func(target int) bool { return target != NoState }
+	if d.Finals[d.Start] || slices.ContainsFunc(d.Transitions[d.Start], hasTransition) {
+		return renumbered(d.Complete(TrapState))
+	}
+
+	completed := d.Clone()
+	fillHoles(completed.Transitions, completed.Start)
+
+	return renumbered(completed)
+}
+
+// renumbered drops the unreachable states of d and names the others q0, q1, …
+// in breadth-first order from the start state.
+func renumbered(d *DFA) *DFA {
+	classes := make([]int, len(d.States))
+	for state := range classes {
+		classes[state] = state
+	}
+	order, next := quotient(d.Transitions, d.Start, classes)
+
+	minimized := NewDFA(quotientNames(len(order)), d.Alphabet)
+	for class, state := range order {
+		minimized.Finals[class] = d.Finals[state]
+		minimized.Transitions[class] = next[class]
+	}
+
+	return minimized
+}
+
+func MinimizeMealy(m *Mealy) *Mealy {
+	return MinimizeMealyWith(m, Refinement)
+}
+
+func MinimizeMealyWith(m *Mealy, algorithm Algorithm) *Mealy {
+	targets := mealyTargets(m)
+	reachable := reachableStates(targets, m.Start)
+
+	outputs := make([]string, len(m.States))
+	for state, row := range m.Transitions {
+		var signature strings.Builder
+		for _, transition := range row {
+			if transition.Target == NoState && !transition.HasOutput() {
+				signature.WriteString("-\x00")
+			} else {
+				signature.WriteString(transition.Output + "/\x00")
+			}
+		}
+		outputs[state] = signature.String()
+	}
+
+	classes := algorithm.partition(targets, reachable, outputClasses(outputs, reachable))
+	order, next := quotient(targets, m.Start, classes)
+
+	minimized := NewMealy(quotientNames(len(order)), m.Alphabet)
+	for class, state := range order {
+		for symbol, transition := range m.Transitions[state] {
+			if transition.Target != NoState || transition.HasOutput() {
+				minimized.Transitions[class][symbol] = MealyTransition{Target: next[class][symbol], Output: transition.Output}
+			}
+		}
+	}
+
+	return minimized
+}
+
+func mealyTargets(m *Mealy) [][]int {
+	targets := make([][]int, len(m.Transitions))
+	for state, row := range m.Transitions {
+		targets[state] = make([]int, len(row))
+		for symbol, transition := range row {
+			targets[state][symbol] = transition.Target
+		}
+	}
+
+	return targets
+}
+
+func reachableStates(transitions [][]int, start int) []bool {
+	reachable := make([]bool, len(transitions))
+	reachable[start] = true
+	queue := []int{start}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, target := range transitions[state] {
+			if target != NoState && !reachable[target] {
+				reachable[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// outputClasses numbers the distinct outputs of reachable states; unreachable
+// states get NoState and are ignored by the refinement.
+func outputClasses(outputs []string, reachable []bool) []int {
+	classes := make([]int, len(outputs))
+	indexes := make(map[string]int)
+	for state, output := range outputs {
+		if !reachable[state] {
+			classes[state] = NoState
+			continue
+		}
+		if _, ok := indexes[output]; !ok {
+			indexes[output] = len(indexes)
+		}
+		classes[state] = indexes[output]
+	}
+
+	return classes
+}
+
+// refinePartition splits classes until states in one class move to the same
+// classes by every symbol, the k-equivalence refinement used by the
+// minimization tools. A missing transition counts as a class of its own.
+func refinePartition(transitions [][]int, reachable []bool, classes []int) []int {
+	classesNum := countClasses(classes)
+	for {
+		next := make([]int, len(classes))
+		indexes := make(map[string]int)
+		for state := range transitions {
+			if !reachable[state] {
+				next[state] = NoState
+				continue
+			}
+			key := signatureKey(classes, state, transitions[state])
+			if _, ok := indexes[key]; !ok {
+				indexes[key] = len(indexes)
+			}
+			next[state] = indexes[key]
+		}
+
+		classes = next
+		if len(indexes) == classesNum {
+			return classes
+		}
+		classesNum = len(indexes)
+	}
+}
+
+func signatureKey(classes []int, state int, row []int) string {
+	var key strings.Builder
+	key.WriteString(strconv.Itoa(classes[state]))
+	for _, target := range row {
+		key.WriteByte(',')
+		if target == NoState {
+			key.WriteByte('-')
+		} else {
+			key.WriteString(strconv.Itoa(classes[target]))
+		}
+	}
+
+	return key.String()
+}
+
+func countClasses(classes []int) int {
+	seen := make(map[int]struct{})
+	for _, class := range classes {
+		if class != NoState {
+			seen[class] = struct{}{}
+		}
+	}
+
+	return len(seen)
+}
+
+// quotient numbers classes in breadth-first order from the start state and
+// returns a representative state for every class together with the
+// transitions between classes.
+func quotient(transitions [][]int, start int, classes []int) (order []int, next [][]int) {
+	numbers := make(map[int]int)
+	numbers[classes[start]] = 0
+	order = []int{start}
+	for i := 0; i < len(order); i++ {
+		row := newTransitionRow(len(transitions[order[i]]))
+		for symbol, target := range transitions[order[i]] {
+			if target == NoState {
+				continue
+			}
+			number, ok := numbers[classes[target]]
+			if !ok {
+				number = len(order)
+				numbers[classes[target]] = number
+				order = append(order, target)
+			}
+			row[symbol] = number
+		}
+		next = append(next, row)
+	}
+
+	return order, next
+}
+
+func quotientNames(statesNum int) []string {
+	names := make([]string, statesNum)
+	for i := range names {
+		names[i] = "q" + strconv.Itoa(i)
+	}
+
+	return names
+}
@@ -0,0 +1,65 @@
+package automata
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMinimizeDFAAlgorithmsAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for i := 0; i < 300; i++ {
+		d := randomDFA(rng, 1+rng.Intn(20), 1+rng.Intn(3))
+		if i%3 != 0 {
+			withHoles(rng, d.Transitions)
+		}
+
+		refinement := MinimizeDFAWith(d, Refinement)
+		if difference := Equivalent(d, refinement); difference != nil {
+			t.Fatalf("DFA %d: minimized DFA differs on %v", i, difference)
+		}
+		if d.IsComplete() && !refinement.IsComplete() {
+			t.Fatalf("DFA %d: minimizing a complete DFA gives a partial one", i)
+		}
+		for _, algorithm := range []Algorithm{Hopcroft, Brzozowski} {
+			if minimized := MinimizeDFAWith(d, algorithm); !reflect.DeepEqual(refinement, minimized) {
+				t.Fatalf("DFA %d: %s gives\n%v\nrefinement gives\n%v", i, algorithm, minimized, refinement)
+			}
+		}
+	}
+}
+
+func TestMinimizeBrzozowskiMatchesDeterminized(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for i := 0; i < 300; i++ {
+		n := randomNFA(rng, 1+rng.Intn(8), 1+rng.Intn(3))
+		brzozowski := MinimizeBrzozowski(n)
+		dfa, _ := Determinize(n)
+		if difference := Equivalent(dfa, brzozowski); difference != nil {
+			t.Fatalf("NFA %d: brzozowski differs from the subset construction on %v", i, difference)
+		}
+
+		for _, algorithm := range []Algorithm{Refinement, Hopcroft} {
+			minimized := MinimizeDFAWith(dfa, algorithm)
+			if !dfa.IsComplete() && !reflect.DeepEqual(brzozowski, minimized) {
+				t.Fatalf("NFA %d: %s gives\n%v\nbrzozowski gives\n%v", i, algorithm, minimized, brzozowski)
+			}
+			if len(withoutDeadState(minimized).States) != len(brzozowski.States) {
+				t.Fatalf("NFA %d: %s gives %d live states, brzozowski %d", i, algorithm, len(withoutDeadState(minimized).States), len(brzozowski.States))
+			}
+		}
+	}
+}
+
+func TestMinimizeDFAEmptyLanguage(t *testing.T) {
+	d := NewDFA([]string{"a", "b"}, Alphabet{"x"})
+	d.Transitions[0][0] = 1
+	d.Transitions[1][0] = 0
+
+	for _, algorithm := range []Algorithm{Refinement, Hopcroft, Brzozowski} {
+		minimized := MinimizeDFAWith(d, algorithm)
+		if len(minimized.States) != 1 || minimized.Transitions[0][0] != 0 {
+			t.Errorf("%s gives %v, want a single dead state", algorithm, minimized)
+		}
+	}
+}
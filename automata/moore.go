@@ -0,0 +1,116 @@
+package automata
+
+import (
+	"fmt"
+	"slices"
+)
+
+type Moore struct {
+	States      []string
+	Alphabet    Alphabet
+	Outputs     []string
+	Start       int
+	Transitions [][]int
+}
+
+func NewMoore(states []string, alphabet Alphabet) *Moore {
+	return &Moore{
+		States:      slices.Clone(states),
+		Alphabet:    slices.Clone(alphabet),
+		Outputs:     make([]string, len(states)),
+		Start:       0,
+		Transitions: newTransitionTable(len(states), len(alphabet)),
+	}
+}
+
+func (m *Moore) StateIndex(name string) int {
+	return stateIndex(m.States, name)
+}
+
+func (m *Moore) Next(state, symbol int) int {
+	return m.Transitions[state][symbol]
+}
+
+func (m *Moore) IsComplete() bool {
+	for _, row := range m.Transitions {
+		if slices.Contains(row, NoState) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Moore) Validate() error {
+	if err := validateStates(m.States, m.Alphabet, m.Start); err != nil {
+		return err
+	}
+	if len(m.Outputs) != len(m.States) {
+		return fmt.Errorf("moore machine has %d outputs for %d states", len(m.Outputs), len(m.States))
+	}
+	if len(m.Transitions) != len(m.States) {
+		return fmt.Errorf("moore machine has %d transition rows for %d states", len(m.Transitions), len(m.States))
+	}
+	for from, row := range m.Transitions {
+		if len(row) != len(m.Alphabet) {
+			return fmt.Errorf("state %s has %d transitions for %d input symbols", m.States[from], len(row), len(m.Alphabet))
+		}
+		for symbol, target := range row {
+			if err := validateTarget(m.States, m.Alphabet, from, symbol, target); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Moore) Clone() *Moore {
+	return &Moore{
+		States:      slices.Clone(m.States),
+		Alphabet:    slices.Clone(m.Alphabet),
+		Outputs:     slices.Clone(m.Outputs),
+		Start:       m.Start,
+		Transitions: cloneTransitionTable(m.Transitions),
+	}
+}
+
+// ToDFA marks every state whose output equals finalOutput as final.
+func (m *Moore) ToDFA(finalOutput string) *DFA {
+	d := NewDFA(m.States, m.Alphabet)
+	d.Start = m.Start
+	d.Transitions = cloneTransitionTable(m.Transitions)
+	for i, output := range m.Outputs {
+		d.Finals[i] = output == finalOutput
+	}
+
+	return d
+}
+
+// IsAcceptor reports whether every output is finalOutput or empty, so the
+// machine is a DFA written as a Moore machine.
+func (m *Moore) IsAcceptor(finalOutput string) bool {
+	for _, output := range m.Outputs {
+		if output != finalOutput && output != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToMealy moves the output of every state onto the transitions leading into it.
+func (m *Moore) ToMealy() *Mealy {
+	mealy := NewMealy(m.States, m.Alphabet)
+	mealy.Start = m.Start
+	for from, row := range m.Transitions {
+		for symbol, target := range row {
+			if target == NoState {
+				continue
+			}
+			mealy.Transitions[from][symbol] = MealyTransition{Target: target, Output: m.Outputs[target]}
+		}
+	}
+
+	return mealy
+}
@@ -0,0 +1,174 @@
+package automata
+
+import (
+	"fmt"
+	"slices"
+)
+
+type NFA struct {
+	States      []string
+	Alphabet    Alphabet
+	Start       int
+	Finals      []bool
+	Transitions [][][]int
+	Epsilon     [][]int
+}
+
+func NewNFA(states []string, alphabet Alphabet) *NFA {
+	transitions := make([][][]int, len(states))
+	for i := range transitions {
+		transitions[i] = make([][]int, len(alphabet))
+	}
+
+	return &NFA{
+		States:      slices.Clone(states),
+		Alphabet:    slices.Clone(alphabet),
+		Start:       0,
+		Finals:      make([]bool, len(states)),
+		Transitions: transitions,
+		Epsilon:     make([][]int, len(states)),
+	}
+}
+
+func (n *NFA) StateIndex(name string) int {
+	return stateIndex(n.States, name)
+}
+
+func (n *NFA) AddState(name string) int {
+	n.States = append(n.States, name)
+	n.Finals = append(n.Finals, false)
+	n.Transitions = append(n.Transitions, make([][]int, len(n.Alphabet)))
+	n.Epsilon = append(n.Epsilon, nil)
+
+	return len(n.States) - 1
+}
+
+func (n *NFA) AddTransition(from, symbol, to int) {
+	if !slices.Contains(n.Transitions[from][symbol], to) {
+		n.Transitions[from][symbol] = append(n.Transitions[from][symbol], to)
+	}
+}
+
+func (n *NFA) AddEpsilon(from, to int) {
+	if !slices.Contains(n.Epsilon[from], to) {
+		n.Epsilon[from] = append(n.Epsilon[from], to)
+	}
+}
+
+func (n *NFA) HasEpsilon() bool {
+	for _, targets := range n.Epsilon {
+		if len(targets) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EpsilonClosure returns the sorted set of states reachable from states by
+// ε-transitions only, including the states themselves.
+func (n *NFA) EpsilonClosure(states []int) []int {
+	visited := make([]bool, len(n.States))
+	stack := make([]int, 0, len(states))
+	for _, state := range states {
+		if !visited[state] {
+			visited[state] = true
+			stack = append(stack, state)
+		}
+	}
+
+	closure := make([]int, 0, len(states))
+	for len(stack) > 0 {
+		state := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		closure = append(closure, state)
+		for _, target := range n.Epsilon[state] {
+			if !visited[target] {
+				visited[target] = true
+				stack = append(stack, target)
+			}
+		}
+	}
+	slices.Sort(closure)
+
+	return closure
+}
+
+func (n *NFA) Validate() error {
+	if err := validateStates(n.States, n.Alphabet, n.Start); err != nil {
+		return err
+	}
+	if len(n.Finals) != len(n.States) {
+		return fmt.Errorf("nfa has %d final marks for %d states", len(n.Finals), len(n.States))
+	}
+	if len(n.Transitions) != len(n.States) || len(n.Epsilon) != len(n.States) {
+		return fmt.Errorf("nfa transition rows do not match %d states", len(n.States))
+	}
+	for from, row := range n.Transitions {
+		if len(row) != len(n.Alphabet) {
+			return fmt.Errorf("state %s has %d transitions for %d input symbols", n.States[from], len(row), len(n.Alphabet))
+		}
+		for symbol, targets := range row {
+			for _, target := range targets {
+				if target < 0 || target >= len(n.States) {
+					return fmt.Errorf("transition %s --%s--> %d points to unknown state", n.States[from], n.Alphabet[symbol], target)
+				}
+			}
+		}
+		for _, target := range n.Epsilon[from] {
+			if target < 0 || target >= len(n.States) {
+				return fmt.Errorf("ε-transition from %s points to unknown state %d", n.States[from], target)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *NFA) Clone() *NFA {
+	transitions := make([][][]int, len(n.Transitions))
+	for i := range n.Transitions {
+		transitions[i] = make([][]int, len(n.Transitions[i]))
+		for j := range n.Transitions[i] {
+			transitions[i][j] = slices.Clone(n.Transitions[i][j])
+		}
+	}
+	epsilon := make([][]int, len(n.Epsilon))
+	for i := range n.Epsilon {
+		epsilon[i] = slices.Clone(n.Epsilon[i])
+	}
+
+	return &NFA{
+		States:      slices.Clone(n.States),
+		Alphabet:    slices.Clone(n.Alphabet),
+		Start:       n.Start,
+		Finals:      slices.Clone(n.Finals),
+		Transitions: transitions,
+		Epsilon:     epsilon,
+	}
+}
+
+// ToDFA reinterprets an NFA that has no ε-transitions and at most one target
+// per symbol as a DFA, without running the subset construction.
+func (n *NFA) ToDFA() (*DFA, error) {
+	if n.HasEpsilon() {
+		return nil, fmt.Errorf("nfa has ε-transitions")
+	}
+
+	dfa := NewDFA(n.States, n.Alphabet)
+	dfa.Start = n.Start
+	copy(dfa.Finals, n.Finals)
+	for state, row := range n.Transitions {
+		for symbol, targets := range row {
+			switch len(targets) {
+			case 0:
+			case 1:
+				dfa.Transitions[state][symbol] = targets[0]
+			default:
+				return nil, fmt.Errorf("state %s has %d transitions by %s", n.States[state], len(targets), n.Alphabet[symbol])
+			}
+		}
+	}
+
+	return dfa, nil
+}
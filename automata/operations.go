@@ -0,0 +1,149 @@
+package automata
+
+import "strconv"
+
+// Complement accepts the words over its alphabet that d rejects. The alphabet
+// is that of d followed by the symbols of extra it lacks, and d is completed
+// with a trap state first, so the result is complete even when d is not.
+func Complement(d *DFA, extra Alphabet) *DFA {
+	extended := d.Clone()
+	for _, symbol := range extra {
+		if extended.Alphabet.Index(symbol) == -1 {
+			extended.Alphabet = append(extended.Alphabet, symbol)
+			for state := range extended.Transitions {
+				extended.Transitions[state] = append(extended.Transitions[state], NoState)
+			}
+		}
+	}
+
+	complement := extended.Complete(TrapState)
+	for state, final := range complement.Finals {
+		complement.Finals[state] = !final
+	}
+
+	return complement
+}
+
+// Reverse accepts the mirror images of the words n accepts. Every transition
+// is turned around, the start state becomes the only final state, and the
+// final states become the start: the single final state itself, or a new
+// state with ε-transitions to all of them.
+func Reverse(n *NFA) *NFA {
+	reversed, finals := reverseTransitions(n)
+	if len(finals) == 1 {
+		reversed.Start = finals[0]
+		return reversed
+	}
+
+	reversed.Start = reversed.AddState(uniqueName(reversed.States, "S"+strconv.Itoa(len(reversed.States))))
+	for _, final := range finals {
+		reversed.AddEpsilon(reversed.Start, final)
+	}
+
+	return reversed
+}
+
+// reverseTransitions turns every transition of n around and makes its start
+// state the only final one. The start of the result is left to the caller,
+// which gets the final states of n for it.
+func reverseTransitions(n *NFA) (reversed *NFA, finals []int) {
+	reversed = NewNFA(n.States, n.Alphabet)
+	for from := range n.States {
+		for symbol, targets := range n.Transitions[from] {
+			for _, to := range targets {
+				reversed.AddTransition(to, symbol, from)
+			}
+		}
+		for _, to := range n.Epsilon[from] {
+			reversed.AddEpsilon(to, from)
+		}
+	}
+	reversed.Finals[n.Start] = true
+
+	for state, final := range n.Finals {
+		if final {
+			finals = append(finals, state)
+		}
+	}
+
+	return reversed, finals
+}
+
+// Concatenate accepts a word of a followed by a word of b. The states of a
+// and then of b are renamed S0, S1, …, and ε-transitions lead from the final
+// states of a to the start of b.
+func Concatenate(a, b *NFA) *NFA {
+	alphabet := append(Alphabet{}, a.Alphabet...)
+	for _, symbol := range b.Alphabet {
+		if alphabet.Index(symbol) == -1 {
+			alphabet = append(alphabet, symbol)
+		}
+	}
+
+	result := NewNFA(nil, alphabet)
+	aOffset := result.appendStates(a)
+	bOffset := result.appendStates(b)
+	result.Start = aOffset + a.Start
+	for state, final := range a.Finals {
+		if final {
+			result.AddEpsilon(aOffset+state, bOffset+b.Start)
+		}
+	}
+	for state, final := range b.Finals {
+		result.Finals[bOffset+state] = final
+	}
+
+	return result
+}
+
+// Star accepts any number of words of n, none included. A new final start
+// state S0 leads to the start of n by ε, and the final states of n lead back
+// to it.
+func Star(n *NFA) *NFA {
+	result := NewNFA(nil, n.Alphabet)
+	start := result.AddState("S0")
+	offset := result.appendStates(n)
+	result.Start = start
+	result.Finals[start] = true
+	result.AddEpsilon(start, offset+n.Start)
+	for state, final := range n.Finals {
+		if final {
+			result.AddEpsilon(offset+state, start)
+		}
+	}
+
+	return result
+}
+
+// appendStates copies the states and transitions of other, looking its
+// symbols up by name, and returns the index of its first state. Copies are
+// named S<index>.
+func (n *NFA) appendStates(other *NFA) int {
+	offset := len(n.States)
+	for range other.States {
+		n.AddState("S" + strconv.Itoa(len(n.States)))
+	}
+	for from := range other.States {
+		for symbol, targets := range other.Transitions[from] {
+			for _, to := range targets {
+				n.AddTransition(offset+from, n.Alphabet.Index(other.Alphabet[symbol]), offset+to)
+			}
+		}
+		for _, to := range other.Epsilon[from] {
+			n.AddEpsilon(offset+from, offset+to)
+		}
+	}
+
+	return offset
+}
+
+// uniqueName returns base, or base followed by the smallest number that makes
+// it differ from every name in states.
+func uniqueName(states []string, base string) string {
+	name := base
+	for i := 1; stateIndex(states, name) != -1; i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	return name
+}
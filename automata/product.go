@@ -0,0 +1,202 @@
+package automata
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Operation decides whether a pair of states of two DFAs accepts, given
+// whether each of them does.
+type Operation int
+
+const (
+	Intersection Operation = iota
+	Union
+	Subtraction
+	SymmetricDifference
+)
+
+var Operations = []Operation{Intersection, Union, Subtraction, SymmetricDifference}
+
+func (o Operation) String() string {
+	switch o {
+	case Intersection:
+		return "intersection"
+	case Union:
+		return "union"
+	case Subtraction:
+		return "difference"
+	case SymmetricDifference:
+		return "symmetric-difference"
+	}
+
+	return fmt.Sprintf("Operation(%d)", int(o))
+}
+
+func ParseOperation(name string) (Operation, error) {
+	for _, operation := range Operations {
+		if operation.String() == name {
+			return operation, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown operation %q", name)
+}
+
+func (o Operation) accepts(left, right bool) bool {
+	switch o {
+	case Intersection:
+		return left && right
+	case Union:
+		return left || right
+	case Subtraction:
+		return left && !right
+	}
+
+	return left != right
+}
+
+// Product builds the DFA of a and b running side by side. The alphabet is
+// that of a followed by the symbols only b has. A symbol missing from one
+// alphabet and a missing transition both lead that side to an implicit dead
+// state, which rejects everything; a pair of two dead states is left out.
+//
+// Only pairs reachable from the pair of start states are built, and pairs from
+// which no accepting pair can be reached are dropped as they behave like the
+// dead state; the start pair is always kept. State i of the result is named Si
+// and stands for pairs[i], a state of a and a state of b, either of them
+// NoState for the dead state.
+func Product(a, b *DFA, operation Operation) (dfa *DFA, pairs [][2]int) {
+	alphabet := append(Alphabet{}, a.Alphabet...)
+	for _, symbol := range b.Alphabet {
+		if alphabet.Index(symbol) == -1 {
+			alphabet = append(alphabet, symbol)
+		}
+	}
+
+	dfa = &DFA{Alphabet: alphabet}
+	indexes := make(map[[2]int]int)
+
+	addPair := func(pair [2]int) int {
+		if index, ok := indexes[pair]; ok {
+			return index
+		}
+
+		index := len(pairs)
+		indexes[pair] = index
+		pairs = append(pairs, pair)
+
+		left := pair[0] != NoState && a.Finals[pair[0]]
+		right := pair[1] != NoState && b.Finals[pair[1]]
+		dfa.States = append(dfa.States, "S"+strconv.Itoa(index))
+		dfa.Finals = append(dfa.Finals, operation.accepts(left, right))
+		dfa.Transitions = append(dfa.Transitions, newTransitionRow(len(alphabet)))
+
+		return index
+	}
+
+	dfa.Start = addPair([2]int{a.Start, b.Start})
+	for current := 0; current < len(pairs); current++ {
+		for symbol, name := range alphabet {
+			next := [2]int{
+				productNext(a, pairs[current][0], name),
+				productNext(b, pairs[current][1], name),
+			}
+			if next[0] == NoState && next[1] == NoState {
+				continue
+			}
+			dfa.Transitions[current][symbol] = addPair(next)
+		}
+	}
+
+	return dropDeadPairs(dfa, pairs)
+}
+
+func dropDeadPairs(dfa *DFA, pairs [][2]int) (*DFA, [][2]int) {
+	live := liveStates(dfa)
+
+	// A dead start pair stays, without transitions, as the DFA of the empty
+	// language.
+	indexes := make([]int, len(dfa.States))
+	trimmed := &DFA{Alphabet: dfa.Alphabet}
+	var trimmedPairs [][2]int
+	for state := range dfa.States {
+		indexes[state] = NoState
+		if live[state] || state == dfa.Start {
+			indexes[state] = len(trimmed.States)
+			trimmed.States = append(trimmed.States, "S"+strconv.Itoa(len(trimmed.States)))
+			trimmed.Finals = append(trimmed.Finals, dfa.Finals[state])
+			trimmedPairs = append(trimmedPairs, pairs[state])
+		}
+	}
+	for state, row := range dfa.Transitions {
+		if indexes[state] == NoState {
+			continue
+		}
+		trimmedRow := newTransitionRow(len(dfa.Alphabet))
+		for symbol, target := range row {
+			if target != NoState && live[target] {
+				trimmedRow[symbol] = indexes[target]
+			}
+		}
+		trimmed.Transitions = append(trimmed.Transitions, trimmedRow)
+	}
+	trimmed.Start = indexes[dfa.Start]
+
+	return trimmed, trimmedPairs
+}
+
+// liveStates marks the states of dfa from which a final state can be reached.
+func liveStates(dfa *DFA) []bool {
+	sources := make([][]int, len(dfa.States))
+	for from, row := range dfa.Transitions {
+		for _, target := range row {
+			if target != NoState {
+				sources[target] = append(sources[target], from)
+			}
+		}
+	}
+
+	live := make([]bool, len(dfa.States))
+	var queue []int
+	for state, final := range dfa.Finals {
+		if final {
+			live[state] = true
+			queue = append(queue, state)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, source := range sources[state] {
+			if !live[source] {
+				live[source] = true
+				queue = append(queue, source)
+			}
+		}
+	}
+
+	return live
+}
+
+func productNext(d *DFA, state int, name string) int {
+	symbol := d.Alphabet.Index(name)
+	if state == NoState || symbol == -1 {
+		return NoState
+	}
+
+	return d.Transitions[state][symbol]
+}
+
+// PairName renders a pair of Product as (p,q) with the state names of a and
+// b, and ∅ for a dead state.
+func PairName(a, b *DFA, pair [2]int) string {
+	name := func(d *DFA, state int) string {
+		if state == NoState {
+			return "∅"
+		}
+		return d.States[state]
+	}
+
+	return "(" + name(a, pair[0]) + "," + name(b, pair[1]) + ")"
+}
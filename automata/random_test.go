@@ -0,0 +1,111 @@
+package automata
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// The machines of the tests and benchmarks, built from seeded random sources
+// so that failures can be replayed.
+
+// randomNames names states s0, s1, … and input symbols x0, x1, ….
+func randomNames(statesNum, symbolsNum int) ([]string, Alphabet) {
+	states := make([]string, statesNum)
+	for i := range states {
+		states[i] = "s" + strconv.Itoa(i)
+	}
+	alphabet := make(Alphabet, symbolsNum)
+	for i := range alphabet {
+		alphabet[i] = "x" + strconv.Itoa(i)
+	}
+
+	return states, alphabet
+}
+
+// namedStates names states S0, S1, … like the tables of NFAToDFA.
+func namedStates(statesNum int) []string {
+	states := make([]string, statesNum)
+	for i := range states {
+		states[i] = "S" + strconv.Itoa(i)
+	}
+
+	return states
+}
+
+// randomMoore builds a complete Moore machine with uniformly random
+// transitions and outputs y0…y(outputsNum-1), for benchmarks and cross-checks.
+func randomMoore(rng *rand.Rand, statesNum, symbolsNum, outputsNum int) *Moore {
+	states, alphabet := randomNames(statesNum, symbolsNum)
+
+	m := NewMoore(states, alphabet)
+	for state := range states {
+		m.Outputs[state] = "y" + strconv.Itoa(rng.Intn(outputsNum))
+		for symbol := range alphabet {
+			m.Transitions[state][symbol] = rng.Intn(statesNum)
+		}
+	}
+
+	return m
+}
+
+func randomDFA(rng *rand.Rand, statesNum, symbolsNum int) *DFA {
+	return randomMoore(rng, statesNum, symbolsNum, 2).ToDFA("y1")
+}
+
+// randomMealy is randomMoore for Mealy machines, with the outputs on the
+// transitions.
+func randomMealy(rng *rand.Rand, statesNum, symbolsNum, outputsNum int) *Mealy {
+	moore := randomMoore(rng, statesNum, symbolsNum, outputsNum)
+
+	m := NewMealy(moore.States, moore.Alphabet)
+	for state, row := range moore.Transitions {
+		for symbol, target := range row {
+			m.Transitions[state][symbol] = MealyTransition{Target: target, Output: "y" + strconv.Itoa(rng.Intn(outputsNum))}
+		}
+	}
+
+	return m
+}
+
+// randomNFA builds an NFA with up to two targets per transition and a few
+// ε-transitions.
+func randomNFA(rng *rand.Rand, statesNum, symbolsNum int) *NFA {
+	states, alphabet := randomNames(statesNum, symbolsNum)
+
+	n := NewNFA(states, alphabet)
+	for state := range states {
+		n.Finals[state] = rng.Intn(4) == 0
+		for symbol := range alphabet {
+			for i := rng.Intn(3); i > 0; i-- {
+				n.AddTransition(state, symbol, rng.Intn(statesNum))
+			}
+		}
+		if rng.Intn(5) == 0 {
+			n.AddEpsilon(state, rng.Intn(statesNum))
+		}
+	}
+
+	return n
+}
+
+// withHoles drops about a tenth of the transitions of a random machine.
+func withHoles(rng *rand.Rand, transitions [][]int) {
+	for _, row := range transitions {
+		for symbol := range row {
+			if rng.Intn(10) == 0 {
+				row[symbol] = NoState
+			}
+		}
+	}
+}
+
+// withMealyHoles is withHoles for Mealy machines.
+func withMealyHoles(rng *rand.Rand, m *Mealy) {
+	for _, row := range m.Transitions {
+		for symbol := range row {
+			if rng.Intn(10) == 0 {
+				row[symbol] = MealyTransition{Target: NoState}
+			}
+		}
+	}
+}
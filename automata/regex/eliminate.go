@@ -0,0 +1,233 @@
+package regex
+
+import (
+	"fmt"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// Order picks which state state elimination removes next.
+type Order int
+
+const (
+	// Natural removes states in table order.
+	Natural Order = iota
+	// Degree removes the state with the fewest in×out edge pairs first.
+	Degree
+	// Weight removes the state whose elimination adds the least expression
+	// size, the heuristic of Delgado and Morais.
+	Weight
+)
+
+var orderNames = []string{"natural", "degree", "weight"}
+
+func (o Order) String() string {
+	if o < 0 || int(o) >= len(orderNames) {
+		return fmt.Sprintf("Order(%d)", int(o))
+	}
+	return orderNames[o]
+}
+
+func ParseOrder(name string) (Order, error) {
+	for i, orderName := range orderNames {
+		if orderName == name {
+			return Order(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown elimination order %q", name)
+}
+
+// FromDFA derives an equivalent expression by state elimination.
+func FromDFA(dfa *automata.DFA, order Order) *Node {
+	return FromNFA(dfa.ToNFA(), order)
+}
+
+// FromNFA derives an equivalent expression by state elimination. States that
+// are unreachable or cannot reach a final state are dropped first.
+func FromNFA(nfa *automata.NFA, order Order) *Node {
+	useful := usefulStates(nfa)
+	if !useful[nfa.Start] {
+		return NewEmptySet()
+	}
+
+	// The generalized NFA gets a fresh start and final state after the
+	// original ones, so that neither has incoming or outgoing edges.
+	statesNum := len(nfa.States)
+	start, final := statesNum, statesNum+1
+	g := newGeneralized(statesNum + 2)
+	g.add(start, nfa.Start, NewEpsilon())
+	for state := range nfa.States {
+		if !useful[state] {
+			continue
+		}
+		if nfa.Finals[state] {
+			g.add(state, final, NewEpsilon())
+		}
+		for symbol, targets := range nfa.Transitions[state] {
+			for _, target := range targets {
+				if useful[target] {
+					g.add(state, target, NewSymbol(nfa.Alphabet[symbol]))
+				}
+			}
+		}
+		for _, target := range nfa.Epsilon[state] {
+			if useful[target] {
+				g.add(state, target, NewEpsilon())
+			}
+		}
+	}
+
+	remaining := make([]int, 0, statesNum)
+	for state := range nfa.States {
+		if useful[state] {
+			remaining = append(remaining, state)
+		}
+	}
+	for len(remaining) > 0 {
+		next := 0
+		if order != Natural {
+			best := -1
+			for i, state := range remaining {
+				if cost := g.cost(state, order); best == -1 || cost < best {
+					next, best = i, cost
+				}
+			}
+		}
+		g.eliminate(remaining[next])
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+
+	if g.edges[start][final] == nil {
+		return NewEmptySet()
+	}
+	return g.edges[start][final]
+}
+
+func usefulStates(nfa *automata.NFA) []bool {
+	statesNum := len(nfa.States)
+	forward := make([][]int, statesNum)
+	backward := make([][]int, statesNum)
+	for state := range nfa.States {
+		targets := append([]int(nil), nfa.Epsilon[state]...)
+		for _, symbolTargets := range nfa.Transitions[state] {
+			targets = append(targets, symbolTargets...)
+		}
+		for _, target := range targets {
+			forward[state] = append(forward[state], target)
+			backward[target] = append(backward[target], state)
+		}
+	}
+
+	reachable := mark(forward, []int{nfa.Start})
+	var finals []int
+	for state, final := range nfa.Finals {
+		if final {
+			finals = append(finals, state)
+		}
+	}
+	coreachable := mark(backward, finals)
+
+	useful := make([]bool, statesNum)
+	for state := range useful {
+		useful[state] = reachable[state] && coreachable[state]
+	}
+	return useful
+}
+
+func mark(edges [][]int, from []int) []bool {
+	marked := make([]bool, len(edges))
+	queue := append([]int(nil), from...)
+	for _, state := range from {
+		marked[state] = true
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, target := range edges[state] {
+			if !marked[target] {
+				marked[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	return marked
+}
+
+// generalized is an NFA whose edges are labelled by expressions; nil means
+// no edge.
+type generalized struct {
+	edges [][]*Node
+}
+
+func newGeneralized(statesNum int) *generalized {
+	edges := make([][]*Node, statesNum)
+	for i := range edges {
+		edges[i] = make([]*Node, statesNum)
+	}
+	return &generalized{edges: edges}
+}
+
+func (g *generalized) add(from, to int, label *Node) {
+	if g.edges[from][to] == nil {
+		g.edges[from][to] = label
+		return
+	}
+	g.edges[from][to] = union(g.edges[from][to], label)
+}
+
+// neighbours returns the states with an edge into and out of state, loops
+// excluded.
+func (g *generalized) neighbours(state int) (in, out []int) {
+	for other := range g.edges {
+		if other == state {
+			continue
+		}
+		if g.edges[other][state] != nil {
+			in = append(in, other)
+		}
+		if g.edges[state][other] != nil {
+			out = append(out, other)
+		}
+	}
+	return in, out
+}
+
+func (g *generalized) cost(state int, order Order) int {
+	in, out := g.neighbours(state)
+	if order == Degree {
+		return len(in) * len(out)
+	}
+
+	weight := 0
+	for _, from := range in {
+		weight += g.edges[from][state].Size() * (len(out) - 1)
+	}
+	for _, to := range out {
+		weight += g.edges[state][to].Size() * (len(in) - 1)
+	}
+	if loop := g.edges[state][state]; loop != nil {
+		weight += loop.Size() * (len(in)*len(out) - 1)
+	}
+	return weight
+}
+
+// eliminate removes state, relabelling every path from → state → to with
+// from-label loop* to-label.
+func (g *generalized) eliminate(state int) {
+	in, out := g.neighbours(state)
+	loop := NewEpsilon()
+	if g.edges[state][state] != nil {
+		loop = star(g.edges[state][state])
+	}
+
+	for _, from := range in {
+		for _, to := range out {
+			g.add(from, to, concat(g.edges[from][state], loop, g.edges[state][to]))
+		}
+	}
+	for other := range g.edges {
+		g.edges[other][state] = nil
+		g.edges[state][other] = nil
+	}
+}
@@ -0,0 +1,67 @@
+package regex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+var orders = []Order{Natural, Degree, Weight}
+
+func TestFromDFARoundTrip(t *testing.T) {
+	patterns := []string{
+		"[a-c]x",
+		"a+b?",
+		"(a(b|c)*)+",
+		"((ab)|c)*d",
+		"(a|b)*a(a|b)(a|b)",
+		"ε",
+		"∅",
+		"(ab*c|ba*)*",
+	}
+	for _, pattern := range patterns {
+		dfa := compileDFA(t, pattern)
+		for _, order := range orders {
+			derived := FromDFA(dfa, order)
+			back, _ := automata.Determinize(Compile(derived))
+			if difference := automata.Equivalent(dfa, back); difference != nil {
+				t.Errorf("%s by %s elimination gives %s, which differs on %v", pattern, order, derived, difference)
+			}
+		}
+	}
+}
+
+func TestFromDFARandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		dfa := automata.NewDFA([]string{"s0", "s1", "s2", "s3"}, automata.Alphabet{"a", "b"})
+		for state := range dfa.States {
+			dfa.Finals[state] = rng.Intn(3) == 0
+			for symbol := range dfa.Alphabet {
+				if target := rng.Intn(len(dfa.States) + 1); target < len(dfa.States) {
+					dfa.Transitions[state][symbol] = target
+				}
+			}
+		}
+
+		for _, order := range orders {
+			derived := FromDFA(dfa, order)
+			back, _ := automata.Determinize(Compile(derived))
+			if difference := automata.Equivalent(dfa, back); difference != nil {
+				t.Fatalf("DFA %d by %s elimination gives %s, which differs on %v", i, order, derived, difference)
+			}
+		}
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	for _, order := range orders {
+		if parsed, err := ParseOrder(order.String()); err != nil || parsed != order {
+			t.Errorf("order %s parses as %v, %v", order, parsed, err)
+		}
+	}
+	if _, err := ParseOrder("random"); err == nil {
+		t.Error("unknown order parses")
+	}
+}
@@ -0,0 +1,204 @@
+package regex
+
+import "fmt"
+
+type parser struct {
+	pattern []rune
+	pos     int
+}
+
+func Parse(pattern string) (*Node, error) {
+	p := &parser{pattern: []rune(pattern)}
+	node, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+
+	return node, nil
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.pattern)
+}
+
+func (p *parser) peek() rune {
+	return p.pattern[p.pos]
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf(format+" at position %d", append(args, p.pos+1)...)
+}
+
+func (p *parser) parseUnion() (*Node, error) {
+	var children []*Node
+	for {
+		child, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+
+		if p.done() || p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return NewUnion(children...), nil
+}
+
+func (p *parser) parseConcat() (*Node, error) {
+	var children []*Node
+	for !p.done() && p.peek() != '|' && p.peek() != ')' {
+		child, err := p.parseRepeat()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	switch len(children) {
+	case 0:
+		return NewEpsilon(), nil
+	case 1:
+		return children[0], nil
+	}
+	return NewConcat(children...), nil
+}
+
+func (p *parser) parseRepeat() (*Node, error) {
+	node, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.done() {
+		switch p.peek() {
+		case '*':
+			node = NewStar(node)
+		case '+':
+			node = NewPlus(node)
+		case '?':
+			node = NewOptional(node)
+		default:
+			return node, nil
+		}
+		p.pos++
+	}
+
+	return node, nil
+}
+
+func (p *parser) parseAtom() (*Node, error) {
+	char := p.peek()
+	p.pos++
+
+	switch char {
+	case '(':
+		node, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek() != ')' {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		return node, nil
+	case '[':
+		return p.parseClass()
+	case '<':
+		return p.parseName()
+	case '\\':
+		if p.done() {
+			return nil, p.errorf("trailing \\")
+		}
+		p.pos++
+		return NewSymbol(string(p.pattern[p.pos-1])), nil
+	case 'ε':
+		return NewEpsilon(), nil
+	case '∅':
+		return NewEmptySet(), nil
+	case '*', '+', '?':
+		p.pos--
+		return nil, p.errorf("nothing to repeat before %q", char)
+	case ']', '>':
+		p.pos--
+		return nil, p.errorf("unexpected %q", char)
+	}
+
+	return NewSymbol(string(char)), nil
+}
+
+// parseClass reads the rest of [abx-z] as a union of symbols.
+func (p *parser) parseClass() (*Node, error) {
+	var symbols []*Node
+	seen := make(map[rune]struct{})
+	add := func(char rune) {
+		if _, ok := seen[char]; !ok {
+			seen[char] = struct{}{}
+			symbols = append(symbols, NewSymbol(string(char)))
+		}
+	}
+
+	if !p.done() && p.peek() == '^' {
+		return nil, p.errorf("negated classes are not supported")
+	}
+	for !p.done() && p.peek() != ']' {
+		char := p.peek()
+		p.pos++
+		if char == '\\' {
+			if p.done() {
+				return nil, p.errorf("trailing \\")
+			}
+			char = p.peek()
+			p.pos++
+		}
+
+		if p.pos+1 < len(p.pattern) && p.peek() == '-' && p.pattern[p.pos+1] != ']' {
+			last := p.pattern[p.pos+1]
+			if last < char {
+				return nil, p.errorf("invalid range %c-%c", char, last)
+			}
+			for c := char; c <= last; c++ {
+				add(c)
+			}
+			p.pos += 2
+			continue
+		}
+		add(char)
+	}
+	if p.done() {
+		return nil, p.errorf("missing ]")
+	}
+	p.pos++
+
+	switch len(symbols) {
+	case 0:
+		return NewEmptySet(), nil
+	case 1:
+		return symbols[0], nil
+	}
+	return NewUnion(symbols...), nil
+}
+
+func (p *parser) parseName() (*Node, error) {
+	start := p.pos
+	for !p.done() && p.peek() != '>' {
+		p.pos++
+	}
+	if p.done() {
+		return nil, p.errorf("missing >")
+	}
+	if p.pos == start {
+		return nil, p.errorf("empty symbol name")
+	}
+	p.pos++
+
+	return NewSymbol(string(p.pattern[start : p.pos-1])), nil
+}
@@ -0,0 +1,105 @@
+package regex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// compileDFA parses a pattern and determinizes its Thompson NFA.
+func compileDFA(t *testing.T, pattern string) *automata.DFA {
+	t.Helper()
+	node, err := Parse(pattern)
+	if err != nil {
+		t.Fatalf("pattern %s: %v", pattern, err)
+	}
+	dfa, _ := automata.Determinize(Compile(node))
+
+	return dfa
+}
+
+// accepts runs a word of one-character symbols.
+func accepts(t *testing.T, dfa *automata.DFA, word string) bool {
+	t.Helper()
+	accepted, err := dfa.Accepts(strings.Split(word, ""))
+	if err != nil {
+		// A symbol outside the alphabet of the pattern is never matched.
+		return false
+	}
+
+	return accepted
+}
+
+func TestCompileMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		accepted []string
+		rejected []string
+	}{
+		{"[a-c]x", []string{"ax", "bx", "cx"}, []string{"dx", "x", "abx"}},
+		{"a+", []string{"a", "aaa"}, []string{"", "b"}},
+		{"ab?c", []string{"ac", "abc"}, []string{"abbc", "a"}},
+		{"(a(b|c)*)+", []string{"a", "abca", "aacb"}, []string{"", "b", "ba"}},
+		{"((ab)|c)*d", []string{"d", "abd", "cabcd"}, []string{"ad", "abab"}},
+		{"ε|a", []string{"", "a"}, []string{"aa"}},
+	}
+	for _, test := range tests {
+		dfa := compileDFA(t, test.pattern)
+		for _, word := range test.accepted {
+			if !accepts(t, dfa, word) {
+				t.Errorf("%s rejects %q", test.pattern, word)
+			}
+		}
+		for _, word := range test.rejected {
+			if accepts(t, dfa, word) {
+				t.Errorf("%s accepts %q", test.pattern, word)
+			}
+		}
+	}
+}
+
+func TestCompileNamedSymbols(t *testing.T) {
+	dfa := compileDFA(t, "<x1>\\*<x2>?")
+	for word, want := range map[string]bool{"x1 *": true, "x1 * x2": true, "x1 x2": false} {
+		if accepted, _ := dfa.Accepts(strings.Fields(word)); accepted != want {
+			t.Errorf("word %q: accepted %v, want %v", word, accepted, want)
+		}
+	}
+}
+
+func TestSugarIsEquivalentToCore(t *testing.T) {
+	pairs := [][2]string{
+		{"[a-c]", "a|b|c"},
+		{"a+", "aa*"},
+		{"a?", "ε|a"},
+		{"((a|b)c)*", "(ac|bc)*"},
+		{"(a*)*", "a*"},
+	}
+	for _, pair := range pairs {
+		if difference := automata.Equivalent(compileDFA(t, pair[0]), compileDFA(t, pair[1])); difference != nil {
+			t.Errorf("%s and %s differ on %v", pair[0], pair[1], difference)
+		}
+	}
+}
+
+func TestStringParsesBack(t *testing.T) {
+	for _, pattern := range []string{"[a-c]x", "(a(b|c)*)+", "ab?c", "a|ε", "<x1>a*", "\\(\\|"} {
+		node, err := Parse(pattern)
+		if err != nil {
+			t.Fatalf("pattern %s: %v", pattern, err)
+		}
+		printed := node.String()
+		if difference := automata.Equivalent(compileDFA(t, pattern), compileDFA(t, printed)); difference != nil {
+			t.Errorf("%s prints as %s, which differs on %v", pattern, printed, difference)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, pattern := range []string{"(a", "a)", "*a", "[a", "[c-a]", "<x1", "a\\"} {
+		if _, err := Parse(pattern); err == nil {
+			t.Errorf("pattern %s parses", pattern)
+		}
+	}
+}
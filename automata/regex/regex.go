@@ -0,0 +1,156 @@
+// Package regex parses regular expressions over the symbols of a machine,
+// compiles them to ε-NFAs, derives them back from machines by state
+// elimination and prints them.
+//
+// Syntax: a|b for union, ab for concatenation, a*, a+ and a? for repetition,
+// (…) for grouping, [abx-z] for classes, ε for the empty word and ∅ for the
+// empty language. Symbols are single characters, \c escapes a special one and
+// <name> stands for a symbol with a longer name such as <x1>.
+package regex
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+type Kind int
+
+const (
+	EmptySet Kind = iota
+	Epsilon
+	Symbol
+	Concat
+	Union
+	Star
+	Plus
+	Optional
+)
+
+type Node struct {
+	Kind     Kind
+	Symbol   string
+	Children []*Node
+}
+
+func NewEmptySet() *Node {
+	return &Node{Kind: EmptySet}
+}
+
+func NewEpsilon() *Node {
+	return &Node{Kind: Epsilon}
+}
+
+func NewSymbol(symbol string) *Node {
+	return &Node{Kind: Symbol, Symbol: symbol}
+}
+
+func NewConcat(children ...*Node) *Node {
+	return &Node{Kind: Concat, Children: children}
+}
+
+func NewUnion(children ...*Node) *Node {
+	return &Node{Kind: Union, Children: children}
+}
+
+func NewStar(child *Node) *Node {
+	return &Node{Kind: Star, Children: []*Node{child}}
+}
+
+func NewPlus(child *Node) *Node {
+	return &Node{Kind: Plus, Children: []*Node{child}}
+}
+
+func NewOptional(child *Node) *Node {
+	return &Node{Kind: Optional, Children: []*Node{child}}
+}
+
+// Alphabet returns the sorted symbols used in the expression.
+func (n *Node) Alphabet() []string {
+	seen := make(map[string]struct{})
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if node.Kind == Symbol {
+			seen[node.Symbol] = struct{}{}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	alphabet := make([]string, 0, len(seen))
+	for symbol := range seen {
+		alphabet = append(alphabet, symbol)
+	}
+	slices.Sort(alphabet)
+
+	return alphabet
+}
+
+const specialChars = `|*+?()[]\<>εØ∅`
+
+func precedence(kind Kind) int {
+	switch kind {
+	case Union:
+		return 0
+	case Concat:
+		return 1
+	case Star, Plus, Optional:
+		return 2
+	}
+
+	return 3
+}
+
+func (n *Node) String() string {
+	var builder strings.Builder
+	n.write(&builder)
+	return builder.String()
+}
+
+func (n *Node) write(builder *strings.Builder) {
+	writeChild := func(child *Node, minPrecedence int) {
+		if precedence(child.Kind) < minPrecedence {
+			builder.WriteByte('(')
+			child.write(builder)
+			builder.WriteByte(')')
+		} else {
+			child.write(builder)
+		}
+	}
+
+	switch n.Kind {
+	case EmptySet:
+		builder.WriteString("∅")
+	case Epsilon:
+		builder.WriteString("ε")
+	case Symbol:
+		writeSymbol(builder, n.Symbol)
+	case Concat:
+		for _, child := range n.Children {
+			writeChild(child, 2)
+		}
+	case Union:
+		for i, child := range n.Children {
+			if i != 0 {
+				builder.WriteByte('|')
+			}
+			writeChild(child, 1)
+		}
+	case Star, Plus, Optional:
+		writeChild(n.Children[0], 3)
+		builder.WriteByte("*+?"[n.Kind-Star])
+	}
+}
+
+func writeSymbol(builder *strings.Builder, symbol string) {
+	if utf8.RuneCountInString(symbol) != 1 {
+		builder.WriteString("<" + symbol + ">")
+		return
+	}
+	if strings.Contains(specialChars, symbol) {
+		builder.WriteByte('\\')
+	}
+	builder.WriteString(symbol)
+}
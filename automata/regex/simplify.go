@@ -0,0 +1,206 @@
+package regex
+
+import "slices"
+
+// Simplify rebuilds the expression bottom-up with the algebraic identities of
+// the simplifying constructors below.
+func Simplify(node *Node) *Node {
+	children := make([]*Node, len(node.Children))
+	for i, child := range node.Children {
+		children[i] = Simplify(child)
+	}
+
+	switch node.Kind {
+	case Concat:
+		return concat(children...)
+	case Union:
+		return union(children...)
+	case Star:
+		return star(children[0])
+	case Plus:
+		return plus(children[0])
+	case Optional:
+		return optional(children[0])
+	}
+
+	return node
+}
+
+// Nullable reports whether the expression matches the empty word.
+func (n *Node) Nullable() bool {
+	switch n.Kind {
+	case Epsilon, Star, Optional:
+		return true
+	case Concat:
+		for _, child := range n.Children {
+			if !child.Nullable() {
+				return false
+			}
+		}
+		return true
+	case Union:
+		for _, child := range n.Children {
+			if child.Nullable() {
+				return true
+			}
+		}
+		return false
+	case Plus:
+		return n.Children[0].Nullable()
+	}
+
+	return false
+}
+
+// Size counts symbols and operators, used to compare candidate expressions.
+func (n *Node) Size() int {
+	size := 1
+	for _, child := range n.Children {
+		size += child.Size()
+	}
+	return size
+}
+
+func equal(a, b *Node) bool {
+	return a.String() == b.String()
+}
+
+// concat applies ∅r = ∅, εr = r, rr* = r*r = r+, r*r* = r* and r+r* = r*r+ = r+.
+func concat(children ...*Node) *Node {
+	var flat []*Node
+	for _, child := range children {
+		switch child.Kind {
+		case EmptySet:
+			return NewEmptySet()
+		case Epsilon:
+			continue
+		case Concat:
+			flat = append(flat, child.Children...)
+			continue
+		}
+		flat = append(flat, child)
+	}
+
+	var merged []*Node
+	for _, child := range flat {
+		if len(merged) == 0 {
+			merged = append(merged, child)
+			continue
+		}
+
+		last := merged[len(merged)-1]
+		switch {
+		case last.Kind == Star && child.Kind == Star && equal(last, child):
+			continue
+		case last.Kind == Star && equal(last.Children[0], child):
+			merged[len(merged)-1] = plus(child)
+			continue
+		case child.Kind == Star && equal(child.Children[0], last):
+			merged[len(merged)-1] = plus(last)
+			continue
+		case last.Kind == Plus && child.Kind == Star && equal(last.Children[0], child.Children[0]):
+			continue
+		case last.Kind == Star && child.Kind == Plus && equal(last.Children[0], child.Children[0]):
+			merged[len(merged)-1] = child
+			continue
+		}
+		merged = append(merged, child)
+	}
+
+	switch len(merged) {
+	case 0:
+		return NewEpsilon()
+	case 1:
+		return merged[0]
+	}
+	return NewConcat(merged...)
+}
+
+// union applies ∅|r = r, r|r = r and ε|r = r?, keeping the alternatives in a
+// stable order so equal expressions print the same.
+func union(children ...*Node) *Node {
+	var flat []*Node
+	hasEpsilon := false
+	for _, child := range children {
+		switch child.Kind {
+		case EmptySet:
+			continue
+		case Epsilon:
+			hasEpsilon = true
+			continue
+		case Union:
+			flat = append(flat, child.Children...)
+			continue
+		}
+		flat = append(flat, child)
+	}
+
+	slices.SortFunc(flat, func(a, b *Node) int {
+		switch as, bs := a.String(), b.String(); {
+		case as < bs:
+			return -1
+		case as > bs:
+			return 1
+		}
+		return 0
+	})
+	flat = slices.CompactFunc(flat, equal)
+
+	var node *Node
+	switch len(flat) {
+	case 0:
+		if hasEpsilon {
+			return NewEpsilon()
+		}
+		return NewEmptySet()
+	case 1:
+		node = flat[0]
+	default:
+		node = NewUnion(flat...)
+	}
+
+	if hasEpsilon {
+		return optional(node)
+	}
+	return node
+}
+
+// star applies ∅* = ε* = ε and (r*)* = (r+)* = (r?)* = r*.
+func star(child *Node) *Node {
+	switch child.Kind {
+	case EmptySet, Epsilon:
+		return NewEpsilon()
+	case Star:
+		return child
+	case Plus, Optional:
+		return star(child.Children[0])
+	}
+
+	return NewStar(child)
+}
+
+// plus applies ∅+ = ∅, ε+ = ε, (r*)+ = r*, (r+)+ = r+ and (r?)+ = r*.
+func plus(child *Node) *Node {
+	switch child.Kind {
+	case EmptySet, Epsilon, Star, Plus:
+		return child
+	case Optional:
+		return star(child.Children[0])
+	}
+
+	return NewPlus(child)
+}
+
+// optional applies ∅? = ε, r? = r for nullable r and (r+)? = r*.
+func optional(child *Node) *Node {
+	switch {
+	case child.Kind == EmptySet:
+		return NewEpsilon()
+	case child.Kind == Plus:
+		return star(child.Children[0])
+	case child.Nullable():
+		return child
+	}
+
+	return NewOptional(child)
+}
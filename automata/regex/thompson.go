@@ -0,0 +1,72 @@
+package regex
+
+import (
+	"strconv"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+// Compile builds an ε-NFA by Thompson's construction: one start and one final
+// state, with every operator glued together by ε-transitions.
+func Compile(node *Node) *automata.NFA {
+	b := &builder{nfa: automata.NewNFA(nil, node.Alphabet())}
+	start, end := b.build(node)
+	b.nfa.Start = start
+	b.nfa.Finals[end] = true
+
+	return b.nfa
+}
+
+type builder struct {
+	nfa *automata.NFA
+}
+
+func (b *builder) newState() int {
+	return b.nfa.AddState("S" + strconv.Itoa(len(b.nfa.States)))
+}
+
+func (b *builder) build(node *Node) (start, end int) {
+	switch node.Kind {
+	case Symbol:
+		start, end = b.newState(), b.newState()
+		b.nfa.AddTransition(start, b.nfa.Alphabet.Index(node.Symbol), end)
+	case Concat:
+		start, end = b.build(node.Children[0])
+		for _, child := range node.Children[1:] {
+			childStart, childEnd := b.build(child)
+			b.nfa.AddEpsilon(end, childStart)
+			end = childEnd
+		}
+	case Union:
+		start = b.newState()
+		ends := make([]int, 0, len(node.Children))
+		for _, child := range node.Children {
+			childStart, childEnd := b.build(child)
+			b.nfa.AddEpsilon(start, childStart)
+			ends = append(ends, childEnd)
+		}
+		end = b.newState()
+		for _, childEnd := range ends {
+			b.nfa.AddEpsilon(childEnd, end)
+		}
+	case Star, Plus, Optional:
+		start = b.newState()
+		childStart, childEnd := b.build(node.Children[0])
+		end = b.newState()
+		b.nfa.AddEpsilon(start, childStart)
+		b.nfa.AddEpsilon(childEnd, end)
+		if node.Kind != Plus {
+			b.nfa.AddEpsilon(start, end)
+		}
+		if node.Kind != Optional {
+			b.nfa.AddEpsilon(childEnd, childStart)
+		}
+	case Epsilon:
+		start, end = b.newState(), b.newState()
+		b.nfa.AddEpsilon(start, end)
+	default:
+		start, end = b.newState(), b.newState()
+	}
+
+	return start, end
+}
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+func WriteDOT(w io.Writer, g *Graph) error {
+	writer := bufio.NewWriter(w)
+
+	fmt.Fprintln(writer, "digraph machine {")
+	fmt.Fprintln(writer, "\trankdir=LR;")
+	fmt.Fprintln(writer, "\tnode [shape=circle];")
+	for _, node := range g.Nodes {
+		shape := "circle"
+		if node.Final {
+			shape = "doublecircle"
+		}
+		label := node.Label
+		if node.Output != "" {
+			label += "\n" + node.Output
+		}
+		fmt.Fprintf(writer, "\tn%d [label=%s, shape=%s];\n", node.Id, quote(label), shape)
+	}
+	for _, node := range g.Nodes {
+		if node.Start {
+			fmt.Fprintf(writer, "\tstart%d [shape=point];\n", node.Id)
+			fmt.Fprintf(writer, "\tstart%d -> n%d;\n", node.Id, node.Id)
+		}
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(writer, "\tn%d -> n%d [label=%s];\n", edge.From, edge.To, quote(edge.Label))
+	}
+	fmt.Fprintln(writer, "}")
+
+	return writer.Flush()
+}
@@ -0,0 +1,160 @@
+// Package render turns machines into drawable graphs and writes them out as
+// text formats that do not need any external tools.
+package render
+
+import (
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+type Node struct {
+	Label string
+	Id    int
+	Start bool
+	Final bool
+	// Output is the Moore output drawn inside the node under its label.
+	Output string
+}
+
+type Edge struct {
+	From  int
+	To    int
+	Label string
+	Id    int
+}
+
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+func NewGraph() *Graph {
+	return &Graph{Nodes: make([]Node, 0), Edges: make([]Edge, 0)}
+}
+
+func (g *Graph) AddNode(label string) int {
+	id := len(g.Nodes)
+	g.Nodes = append(g.Nodes, Node{Label: label, Id: id})
+	return id
+}
+
+// AddOutputNode adds a node showing a Moore output under its label.
+func (g *Graph) AddOutputNode(label, output string) int {
+	id := g.AddNode(label)
+	g.Nodes[id].Output = output
+	return id
+}
+
+func (g *Graph) MarkStart(id int) {
+	g.Nodes[id].Start = true
+}
+
+func (g *Graph) MarkFinal(id int) {
+	g.Nodes[id].Final = true
+}
+
+// AddEdge appends the label to an existing edge between the same nodes, so a
+// machine gets one arrow per pair of states.
+func (g *Graph) AddEdge(from, to int, label string) int {
+	for i := range g.Edges {
+		if g.Edges[i].From == from && g.Edges[i].To == to {
+			g.Edges[i].Label += ", " + label
+			return g.Edges[i].Id
+		}
+	}
+
+	id := len(g.Edges)
+	g.Edges = append(g.Edges, Edge{From: from, To: to, Label: label, Id: id})
+	return id
+}
+
+func (g *Graph) GetNodes() []Node {
+	return g.Nodes
+}
+
+func (g *Graph) GetEdges() []Edge {
+	return g.Edges
+}
+
+func FromDFA(dfa *automata.DFA) *Graph {
+	g := NewGraph()
+	for state, name := range dfa.States {
+		g.AddNode(name)
+		g.Nodes[state].Final = dfa.Finals[state]
+	}
+	g.Nodes[dfa.Start].Start = true
+
+	for state, row := range dfa.Transitions {
+		for symbol, target := range row {
+			if target != automata.NoState {
+				g.AddEdge(state, target, dfa.Alphabet[symbol])
+			}
+		}
+	}
+
+	return g
+}
+
+func FromNFA(nfa *automata.NFA) *Graph {
+	g := NewGraph()
+	for state, name := range nfa.States {
+		g.AddNode(name)
+		g.Nodes[state].Final = nfa.Finals[state]
+	}
+	g.Nodes[nfa.Start].Start = true
+
+	for state, row := range nfa.Transitions {
+		for symbol, targets := range row {
+			for _, target := range targets {
+				g.AddEdge(state, target, nfa.Alphabet[symbol])
+			}
+		}
+		for _, target := range nfa.Epsilon[state] {
+			g.AddEdge(state, target, "ε")
+		}
+	}
+
+	return g
+}
+
+func FromMoore(moore *automata.Moore) *Graph {
+	g := NewGraph()
+	for state, name := range moore.States {
+		g.AddNode(name)
+		g.Nodes[state].Output = moore.Outputs[state]
+	}
+	g.Nodes[moore.Start].Start = true
+
+	for state, row := range moore.Transitions {
+		for symbol, target := range row {
+			if target != automata.NoState {
+				g.AddEdge(state, target, moore.Alphabet[symbol])
+			}
+		}
+	}
+
+	return g
+}
+
+func FromMealy(mealy *automata.Mealy) *Graph {
+	g := NewGraph()
+	for _, name := range mealy.States {
+		g.AddNode(name)
+	}
+	g.Nodes[mealy.Start].Start = true
+
+	for state, row := range mealy.Transitions {
+		for symbol, transition := range row {
+			if transition.Target != automata.NoState {
+				g.AddEdge(state, transition.Target, mealy.Alphabet[symbol]+"/"+transition.Output)
+			}
+		}
+	}
+
+	return g
+}
+
+func quote(label string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(label) + `"`
+}
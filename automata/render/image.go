@@ -0,0 +1,55 @@
+package render
+
+import (
+	"io"
+	"os"
+	"os/exec"
+)
+
+// GenerateImage always writes outputFileName.dot. The picture is rendered to
+// outputFileName.png by Graphviz when the dot tool is installed and works, and
+// to outputFileName.svg by WriteSVG otherwise.
+func (g *Graph) GenerateImage(outputFileName string) error {
+	err := writeFile(outputFileName+".dot", func(w io.Writer) error {
+		return WriteDOT(w, g)
+	})
+	if err != nil {
+		return err
+	}
+
+	dot, err := exec.LookPath("dot")
+	if err == nil {
+		err = exec.Command(dot, "-Tpng", "-o", outputFileName+".png", outputFileName+".dot").Run()
+	}
+	if err == nil {
+		return nil
+	}
+
+	return writeFile(outputFileName+".svg", func(w io.Writer) error {
+		return WriteSVG(w, g)
+	})
+}
+
+// Export writes the graph as dot, svg, mermaid or plantuml text.
+func (g *Graph) Export(w io.Writer, format string) error {
+	renderFormat, err := ParseFormat(format)
+	if err != nil {
+		return err
+	}
+
+	return Write(w, g, renderFormat)
+}
+
+func writeFile(fileName string, write func(w io.Writer) error) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
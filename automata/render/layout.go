@@ -0,0 +1,181 @@
+package render
+
+import (
+	"math"
+	"slices"
+	"unicode/utf8"
+)
+
+const (
+	charWidth   = 7.5
+	minRadius   = 20.0
+	layerGap    = 110.0
+	nodeGap     = 40.0
+	margin      = 10.0
+	startArrow  = 30.0
+	sweepsCount = 4
+)
+
+type point struct {
+	x, y float64
+}
+
+// layout places nodes in layers by their BFS distance from the start nodes,
+// left to right, and orders every layer by the barycenter of its neighbours
+// to keep crossings down.
+type layout struct {
+	radius float64
+	layer  []int
+	pos    []point
+}
+
+func newLayout(g *Graph) *layout {
+	l := &layout{radius: minRadius, layer: make([]int, len(g.Nodes))}
+	for _, node := range g.Nodes {
+		width := float64(max(utf8.RuneCountInString(node.Label), utf8.RuneCountInString(node.Output))) * charWidth
+		l.radius = max(l.radius, width/2+8)
+	}
+
+	layers := l.assignLayers(g)
+	l.orderLayers(g, layers)
+	l.place(layers)
+
+	return l
+}
+
+func (l *layout) assignLayers(g *Graph) [][]int {
+	adjacent := make([][]int, len(g.Nodes))
+	for _, edge := range g.Edges {
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+	}
+
+	var layers [][]int
+	visited := make([]bool, len(g.Nodes))
+	visit := func(roots []int) {
+		queue := roots
+		for _, root := range roots {
+			visited[root] = true
+		}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			for len(layers) <= l.layer[node] {
+				layers = append(layers, nil)
+			}
+			layers[l.layer[node]] = append(layers[l.layer[node]], node)
+
+			for _, next := range adjacent[node] {
+				if !visited[next] {
+					visited[next] = true
+					l.layer[next] = l.layer[node] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	var starts []int
+	for _, node := range g.Nodes {
+		if node.Start {
+			starts = append(starts, node.Id)
+		}
+	}
+	visit(starts)
+	// Nodes unreachable from the start go after everything else.
+	for _, node := range g.Nodes {
+		if !visited[node.Id] {
+			l.layer[node.Id] = len(layers)
+			visit([]int{node.Id})
+		}
+	}
+
+	return layers
+}
+
+func (l *layout) orderLayers(g *Graph, layers [][]int) {
+	neighbours := make([][]int, len(g.Nodes))
+	for _, edge := range g.Edges {
+		if edge.From != edge.To {
+			neighbours[edge.From] = append(neighbours[edge.From], edge.To)
+			neighbours[edge.To] = append(neighbours[edge.To], edge.From)
+		}
+	}
+
+	index := make([]float64, len(g.Nodes))
+	for _, layer := range layers {
+		for i, node := range layer {
+			index[node] = float64(i)
+		}
+	}
+
+	sweep := func(layer []int, side int) {
+		barycenter := make(map[int]float64, len(layer))
+		for _, node := range layer {
+			sum, count := 0.0, 0
+			for _, other := range neighbours[node] {
+				if l.layer[other] == l.layer[node]+side {
+					sum += index[other]
+					count++
+				}
+			}
+			barycenter[node] = index[node]
+			if count > 0 {
+				barycenter[node] = sum / float64(count)
+			}
+		}
+		slices.SortStableFunc(layer, func(a, b int) int {
+			return compareFloats(barycenter[a], barycenter[b])
+		})
+		for i, node := range layer {
+			index[node] = float64(i)
+		}
+	}
+
+	for i := 0; i < sweepsCount; i++ {
+		for j := 1; j < len(layers); j++ {
+			sweep(layers[j], -1)
+		}
+		for j := len(layers) - 2; j >= 0; j-- {
+			sweep(layers[j], 1)
+		}
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (l *layout) place(layers [][]int) {
+	tallest := 0
+	for _, layer := range layers {
+		tallest = max(tallest, len(layer))
+	}
+
+	step := 2*l.radius + nodeGap
+	l.pos = make([]point, len(l.layer))
+	for i, layer := range layers {
+		offset := float64(tallest-len(layer)) * step / 2
+		for j, node := range layer {
+			l.pos[node] = point{
+				x: l.radius + float64(i)*(2*l.radius+layerGap),
+				y: l.radius + offset + float64(j)*step,
+			}
+		}
+	}
+}
+
+// boundary is the point on the circle around center facing towards.
+func (l *layout) boundary(center, towards point) point {
+	dx, dy := towards.x-center.x, towards.y-center.y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return center
+	}
+	return point{center.x + dx/length*l.radius, center.y + dy/length*l.radius}
+}
@@ -0,0 +1,142 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+const (
+	fontSize     = 12
+	loopHeight   = 36.0
+	edgeBend     = 0.25
+	labelPadding = 4.0
+)
+
+// canvas collects the SVG elements and the box they cover, so the view box
+// can be written once everything is drawn.
+type canvas struct {
+	body     bytes.Buffer
+	min, max point
+}
+
+func newCanvas() *canvas {
+	return &canvas{
+		min: point{math.Inf(1), math.Inf(1)},
+		max: point{math.Inf(-1), math.Inf(-1)},
+	}
+}
+
+func (c *canvas) include(at point, dx, dy float64) {
+	c.min = point{math.Min(c.min.x, at.x-dx), math.Min(c.min.y, at.y-dy)}
+	c.max = point{math.Max(c.max.x, at.x+dx), math.Max(c.max.y, at.y+dy)}
+}
+
+// WriteSVG draws the graph with the built-in layered layout, so no Graphviz
+// installation is needed.
+func WriteSVG(w io.Writer, g *Graph) error {
+	l := newLayout(g)
+	c := newCanvas()
+
+	reversed := make(map[[2]int]bool, len(g.Edges))
+	for _, edge := range g.Edges {
+		reversed[[2]int{edge.To, edge.From}] = true
+	}
+	for _, edge := range g.Edges {
+		if edge.From == edge.To {
+			c.loop(l, edge)
+			continue
+		}
+		straight := !reversed[[2]int{edge.From, edge.To}] && l.layer[edge.To]-l.layer[edge.From] == 1
+		c.edge(l, edge, straight)
+	}
+	for _, node := range g.Nodes {
+		c.node(l, node)
+	}
+	if len(g.Nodes) == 0 {
+		c.include(point{}, 0, 0)
+	}
+
+	writer := bufio.NewWriter(w)
+	x, y := c.min.x-margin, c.min.y-margin
+	width, height := c.max.x-c.min.x+2*margin, c.max.y-c.min.y+2*margin
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="%.1f %.1f %.1f %.1f">`+"\n",
+		math.Ceil(width), math.Ceil(height), x, y, width, height)
+	fmt.Fprintln(writer, `<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>`)
+	fmt.Fprintf(writer, `<g font-family="monospace" font-size="%d" text-anchor="middle" fill="none" stroke="black">`+"\n", fontSize)
+	writer.Write(c.body.Bytes())
+	fmt.Fprintln(writer, "</g>")
+	fmt.Fprintln(writer, "</svg>")
+
+	return writer.Flush()
+}
+
+func (c *canvas) node(l *layout, node Node) {
+	center := l.pos[node.Id]
+	c.include(center, l.radius, l.radius)
+	if node.Start {
+		from := point{center.x - l.radius - startArrow, center.y}
+		c.include(from, 0, 0)
+		fmt.Fprintf(&c.body, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" marker-end="url(#arrow)"/>`+"\n",
+			from.x, from.y, center.x-l.radius, center.y)
+	}
+
+	fmt.Fprintf(&c.body, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="white"/>`+"\n", center.x, center.y, l.radius)
+	if node.Final {
+		fmt.Fprintf(&c.body, `<circle cx="%.1f" cy="%.1f" r="%.1f"/>`+"\n", center.x, center.y, l.radius-4)
+	}
+
+	if node.Output == "" {
+		c.text(point{center.x, center.y + fontSize/3}, node.Label)
+		return
+	}
+	half := l.radius * 0.8
+	fmt.Fprintf(&c.body, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`+"\n", center.x-half, center.y, center.x+half, center.y)
+	c.text(point{center.x, center.y - 4}, node.Label)
+	c.text(point{center.x, center.y + fontSize + 1}, node.Output)
+}
+
+// edge draws a quadratic curve bent to the left of its direction, so a pair
+// of opposite edges does not overlap.
+func (c *canvas) edge(l *layout, edge Edge, straight bool) {
+	from, to := l.pos[edge.From], l.pos[edge.To]
+	mid := point{(from.x + to.x) / 2, (from.y + to.y) / 2}
+	bend := edgeBend
+	if straight {
+		bend = 0
+	}
+	control := point{mid.x + (to.y-from.y)*bend, mid.y - (to.x-from.x)*bend}
+
+	start, end := l.boundary(from, control), l.boundary(to, control)
+	fmt.Fprintf(&c.body, `<path d="M%.1f,%.1f Q%.1f,%.1f %.1f,%.1f" marker-end="url(#arrow)"/>`+"\n",
+		start.x, start.y, control.x, control.y, end.x, end.y)
+
+	// The middle of a quadratic curve is halfway between the chord and the
+	// control point.
+	top := point{
+		0.25*start.x + 0.5*control.x + 0.25*end.x,
+		0.25*start.y + 0.5*control.y + 0.25*end.y,
+	}
+	c.include(top, 0, 0)
+	c.text(point{top.x, top.y - labelPadding}, edge.Label)
+}
+
+func (c *canvas) loop(l *layout, edge Edge) {
+	center := l.pos[edge.From]
+	dx, dy := l.radius*math.Sin(math.Pi/6), l.radius*math.Cos(math.Pi/6)
+	top := center.y - l.radius - loopHeight
+	fmt.Fprintf(&c.body, `<path d="M%.1f,%.1f C%.1f,%.1f %.1f,%.1f %.1f,%.1f" marker-end="url(#arrow)"/>`+"\n",
+		center.x-dx, center.y-dy, center.x-2*dx, top, center.x+2*dx, top, center.x+dx, center.y-dy)
+	c.text(point{center.x, top + 0.25*loopHeight - labelPadding}, edge.Label)
+}
+
+func (c *canvas) text(at point, text string) {
+	width := float64(utf8.RuneCountInString(text)) * charWidth
+	c.include(point{at.x, at.y - fontSize/2}, width/2, fontSize/2)
+	fmt.Fprintf(&c.body, `<text x="%.1f" y="%.1f" fill="black" stroke="white" stroke-width="3" paint-order="stroke">%s</text>`+"\n",
+		at.x, at.y, html.EscapeString(text))
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatDOT      Format = "dot"
+	FormatSVG      Format = "svg"
+	FormatMermaid  Format = "mermaid"
+	FormatPlantUML Format = "plantuml"
+)
+
+var Formats = []Format{FormatDOT, FormatSVG, FormatMermaid, FormatPlantUML}
+
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown format %q", name)
+}
+
+func Write(w io.Writer, g *Graph, format Format) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatSVG:
+		return WriteSVG(w, g)
+	case FormatMermaid:
+		return WriteMermaid(w, g)
+	case FormatPlantUML:
+		return WritePlantUML(w, g)
+	}
+
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// WriteMermaid writes a Mermaid stateDiagram-v2. Moore outputs become part of
+// the state names, as Mermaid states have a single line.
+func WriteMermaid(w io.Writer, g *Graph) error {
+	writer := bufio.NewWriter(w)
+
+	fmt.Fprintln(writer, "stateDiagram-v2")
+	fmt.Fprintln(writer, "    direction LR")
+	writeStates(writer, g, "    ", "    state %s as n%d\n", mermaidText)
+
+	return writer.Flush()
+}
+
+// WritePlantUML writes a PlantUML state diagram, with Moore outputs in the
+// state names like WriteMermaid.
+func WritePlantUML(w io.Writer, g *Graph) error {
+	writer := bufio.NewWriter(w)
+
+	fmt.Fprintln(writer, "@startuml")
+	fmt.Fprintln(writer, "hide empty description")
+	fmt.Fprintln(writer, "left to right direction")
+	writeStates(writer, g, "", "state %s as n%d\n", plantUMLText)
+	fmt.Fprintln(writer, "@enduml")
+
+	return writer.Flush()
+}
+
+// writeStates writes the lines both state diagram syntaxes share: states,
+// [*] arrows for start and final states and labelled transitions.
+func writeStates(w io.Writer, g *Graph, indent, stateFormat string, escape func(string) string) {
+	for _, node := range g.Nodes {
+		label := node.Label
+		if node.Output != "" {
+			label += "/" + node.Output
+		}
+		fmt.Fprintf(w, stateFormat, `"`+escape(label)+`"`, node.Id)
+	}
+	for _, node := range g.Nodes {
+		if node.Start {
+			fmt.Fprintf(w, "%s[*] --> n%d\n", indent, node.Id)
+		}
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(w, "%sn%d --> n%d : %s\n", indent, edge.From, edge.To, escape(edge.Label))
+	}
+	for _, node := range g.Nodes {
+		if node.Final {
+			fmt.Fprintf(w, "%sn%d --> [*]\n", indent, node.Id)
+		}
+	}
+}
+
+// mermaidText replaces the characters Mermaid reads as syntax with entity
+// codes.
+func mermaidText(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", ";", "#59;", "\n", " ").Replace(text)
+}
+
+func plantUMLText(text string) string {
+	return strings.NewReplacer(`"`, "'", "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,104 @@
+package automata
+
+import "fmt"
+
+type TraceStep struct {
+	State  string
+	Input  string
+	Next   string
+	Output string
+}
+
+// Trace records a run of a machine: one output and one step per input symbol.
+// Initial is the output before any input, that of the start state of a Moore
+// machine or DFA and empty for a Mealy machine.
+type Trace struct {
+	Initial string
+	Outputs []string
+	Steps   []TraceStep
+}
+
+func (t *Trace) add(state, input, next, output string) {
+	t.Outputs = append(t.Outputs, output)
+	t.Steps = append(t.Steps, TraceStep{State: state, Input: input, Next: next, Output: output})
+}
+
+// Simulate runs word from the start state. On a missing transition it returns
+// the trace up to that point together with the error.
+func (m *Moore) Simulate(word []string) (*Trace, error) {
+	trace := &Trace{Initial: m.Outputs[m.Start]}
+	state := m.Start
+	for _, input := range word {
+		symbol := m.Alphabet.Index(input)
+		if symbol == -1 {
+			return trace, fmt.Errorf("unknown input symbol %q", input)
+		}
+		next := m.Transitions[state][symbol]
+		if next == NoState {
+			return trace, fmt.Errorf("state %s has no transition by %s", m.States[state], input)
+		}
+		trace.add(m.States[state], input, m.States[next], m.Outputs[next])
+		state = next
+	}
+
+	return trace, nil
+}
+
+// Simulate is Moore.Simulate for Mealy machines. A transition with an output
+// but no next state, written -/y, still outputs y; the run ends there and is
+// an error only when more input follows.
+func (m *Mealy) Simulate(word []string) (*Trace, error) {
+	trace := &Trace{}
+	state := m.Start
+	for i, input := range word {
+		symbol := m.Alphabet.Index(input)
+		if symbol == -1 {
+			return trace, fmt.Errorf("unknown input symbol %q", input)
+		}
+		transition := m.Transitions[state][symbol]
+		if transition.Target == NoState && transition.HasOutput() {
+			trace.add(m.States[state], input, "-", transition.Output)
+			if i < len(word)-1 {
+				return trace, fmt.Errorf("state %s has no next state by %s", m.States[state], input)
+			}
+			return trace, nil
+		}
+		if transition.Target == NoState {
+			return trace, fmt.Errorf("state %s has no transition by %s", m.States[state], input)
+		}
+		trace.add(m.States[state], input, m.States[transition.Target], transition.Output)
+		state = transition.Target
+	}
+
+	return trace, nil
+}
+
+// Simulate outputs "accept" or "reject" after every symbol. A missing
+// transition rejects the rest of the word, so only unknown symbols are errors.
+func (d *DFA) Simulate(word []string) (*Trace, error) {
+	trace := &Trace{Initial: acceptance(d.Finals[d.Start])}
+	state := d.Start
+	for _, input := range word {
+		symbol := d.Alphabet.Index(input)
+		if symbol == -1 {
+			return trace, fmt.Errorf("unknown input symbol %q", input)
+		}
+		next := d.Transitions[state][symbol]
+		if next == NoState {
+			trace.add(d.States[state], input, "-", "reject")
+			return trace, nil
+		}
+		trace.add(d.States[state], input, d.States[next], acceptance(d.Finals[next]))
+		state = next
+	}
+
+	return trace, nil
+}
+
+func acceptance(final bool) string {
+	if final {
+		return "accept"
+	}
+
+	return "reject"
+}
@@ -0,0 +1,39 @@
+package automata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMooreSimulateStartsWithStartOutput(t *testing.T) {
+	m := NewMoore([]string{"a", "b"}, Alphabet{"x"})
+	m.Outputs = []string{"0", "1"}
+	m.Transitions[0][0] = 1
+	m.Transitions[1][0] = 0
+
+	for _, word := range [][]string{{}, {"x", "x"}} {
+		trace, err := m.Simulate(word)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trace.Initial != "0" || len(trace.Outputs) != len(word) {
+			t.Errorf("word %v: trace %+v", word, trace)
+		}
+	}
+}
+
+func TestMealySimulateWithoutNextState(t *testing.T) {
+	// x;-/1 for s0: the output is known, the next state is not.
+	m := NewMealy([]string{"s0"}, Alphabet{"x"})
+	m.Transitions[0][0] = MealyTransition{Target: NoState, Output: "1"}
+
+	trace, err := m.Simulate([]string{"x"})
+	if err != nil || !reflect.DeepEqual(trace.Outputs, []string{"1"}) {
+		t.Errorf("word x: outputs %v, %v", trace.Outputs, err)
+	}
+
+	trace, err = m.Simulate([]string{"x", "x"})
+	if err == nil || !reflect.DeepEqual(trace.Outputs, []string{"1"}) {
+		t.Errorf("word x x: outputs %v, %v, want 1 and an error", trace.Outputs, err)
+	}
+}
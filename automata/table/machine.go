@@ -0,0 +1,319 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+)
+
+const (
+	FinalOutput   = "F"
+	EpsilonSymbol = "e"
+	NoTransition  = "-"
+)
+
+type Kind string
+
+const (
+	KindDFA   Kind = "dfa"
+	KindNFA   Kind = "nfa"
+	KindMoore Kind = "moore"
+	KindMealy Kind = "mealy"
+)
+
+// DetectKind guesses the machine stored in records. Moore-style tables (NFA,
+// DFA and Moore) start with two rows whose first cell is empty: outputs and
+// states. Mealy tables have only the row of states before the inputs.
+func DetectKind(records [][]string) Kind {
+	if len(records) < 2 || len(records[1]) == 0 || records[1][0] != "" {
+		return KindMealy
+	}
+
+	for _, record := range records[2:] {
+		if len(record) == 0 {
+			continue
+		}
+		if record[0] == EpsilonSymbol {
+			return KindNFA
+		}
+		for _, cell := range record[1:] {
+			if strings.Contains(cell, ",") {
+				return KindNFA
+			}
+		}
+	}
+	for _, output := range records[0][min(len(records[0]), 1):] {
+		if output != "" && output != FinalOutput {
+			return KindMoore
+		}
+	}
+
+	return KindDFA
+}
+
+func isNoTransition(cell string) bool {
+	cell = strings.TrimSpace(cell)
+	return cell == "" || cell == NoTransition
+}
+
+// splitRow separates the header cell of a row from the rest; rows can be
+// empty when the whole table is.
+func splitRow(record []string) (string, []string) {
+	if len(record) == 0 {
+		return "", nil
+	}
+	return record[0], record[1:]
+}
+
+type mooreLayout struct {
+	outputs []string
+	states  []string
+	symbols []string
+	cells   [][]string
+}
+
+func readMooreLayout(records [][]string) (*mooreLayout, error) {
+	if len(records) < 2 {
+		return nil, fmt.Errorf("table must have rows of outputs and states")
+	}
+
+	_, states := splitRow(records[1])
+	layout := &mooreLayout{states: states}
+	if len(layout.states) == 0 {
+		return nil, fmt.Errorf("table has no states")
+	}
+	_, outputs := splitRow(records[0])
+	if len(outputs) > len(layout.states) {
+		return nil, fmt.Errorf("table has %d outputs for %d states", len(outputs), len(layout.states))
+	}
+	layout.outputs = make([]string, len(layout.states))
+	copy(layout.outputs, outputs)
+
+	for _, record := range records[2:] {
+		symbol, row := splitRow(record)
+		if len(row) > len(layout.states) {
+			return nil, fmt.Errorf("input %s has %d transitions for %d states", symbol, len(row), len(layout.states))
+		}
+		cells := make([]string, len(layout.states))
+		copy(cells, row)
+		layout.symbols = append(layout.symbols, symbol)
+		layout.cells = append(layout.cells, cells)
+	}
+
+	return layout, nil
+}
+
+func ParseNFA(records [][]string) (*automata.NFA, error) {
+	layout, err := readMooreLayout(records)
+	if err != nil {
+		return nil, err
+	}
+
+	alphabet := make(automata.Alphabet, 0, len(layout.symbols))
+	for _, symbol := range layout.symbols {
+		if symbol != EpsilonSymbol {
+			alphabet = append(alphabet, symbol)
+		}
+	}
+
+	nfa := automata.NewNFA(layout.states, alphabet)
+	for i, output := range layout.outputs {
+		nfa.Finals[i] = output == FinalOutput
+	}
+	for i, symbolName := range layout.symbols {
+		symbol := nfa.Alphabet.Index(symbolName)
+		for state, cell := range layout.cells[i] {
+			if isNoTransition(cell) {
+				continue
+			}
+			for _, targetName := range strings.Split(cell, ",") {
+				target := nfa.StateIndex(strings.TrimSpace(targetName))
+				if target == -1 {
+					return nil, fmt.Errorf("unknown state %s in transition from %s by %s", targetName, nfa.States[state], symbolName)
+				}
+				if symbolName == EpsilonSymbol {
+					nfa.AddEpsilon(state, target)
+				} else {
+					nfa.AddTransition(state, symbol, target)
+				}
+			}
+		}
+	}
+
+	return nfa, nfa.Validate()
+}
+
+func ParseDFA(records [][]string) (*automata.DFA, error) {
+	nfa, err := ParseNFA(records)
+	if err != nil {
+		return nil, err
+	}
+
+	return nfa.ToDFA()
+}
+
+func ParseMoore(records [][]string) (*automata.Moore, error) {
+	layout, err := readMooreLayout(records)
+	if err != nil {
+		return nil, err
+	}
+
+	moore := automata.NewMoore(layout.states, layout.symbols)
+	copy(moore.Outputs, layout.outputs)
+	for symbol, row := range layout.cells {
+		for state, cell := range row {
+			if isNoTransition(cell) {
+				continue
+			}
+			target := moore.StateIndex(strings.TrimSpace(cell))
+			if target == -1 {
+				return nil, fmt.Errorf("unknown state %s in transition from %s by %s", cell, moore.States[state], layout.symbols[symbol])
+			}
+			moore.Transitions[state][symbol] = target
+		}
+	}
+
+	return moore, moore.Validate()
+}
+
+func ParseMealy(records [][]string) (*automata.Mealy, error) {
+	if len(records) == 0 || len(records[0]) < 2 {
+		return nil, fmt.Errorf("table has no states")
+	}
+
+	states := records[0][1:]
+	alphabet := make(automata.Alphabet, 0, len(records)-1)
+	for _, record := range records[1:] {
+		symbol, _ := splitRow(record)
+		alphabet = append(alphabet, symbol)
+	}
+
+	mealy := automata.NewMealy(states, alphabet)
+	for symbol, record := range records[1:] {
+		_, row := splitRow(record)
+		if len(row) > len(states) {
+			return nil, fmt.Errorf("input %s has %d transitions for %d states", alphabet[symbol], len(row), len(states))
+		}
+		for state, cell := range row {
+			if isNoTransition(cell) {
+				continue
+			}
+			targetName, output, found := strings.Cut(cell, "/")
+			if !found {
+				return nil, fmt.Errorf("transition %s from %s by %s must be <state>/<output>", cell, states[state], alphabet[symbol])
+			}
+			target := automata.NoState
+			if !isNoTransition(targetName) {
+				target = mealy.StateIndex(strings.TrimSpace(targetName))
+				if target == -1 {
+					return nil, fmt.Errorf("unknown state %s in transition from %s by %s", targetName, states[state], alphabet[symbol])
+				}
+			}
+			mealy.Transitions[state][symbol] = automata.MealyTransition{Target: target, Output: strings.TrimSpace(output)}
+		}
+	}
+
+	return mealy, mealy.Validate()
+}
+
+func NFARecords(nfa *automata.NFA) [][]string {
+	order := automata.StartFirst(len(nfa.States), nfa.Start)
+
+	outputs := []string{""}
+	states := []string{""}
+	for _, state := range order {
+		states = append(states, nfa.States[state])
+		if nfa.Finals[state] {
+			outputs = append(outputs, FinalOutput)
+		} else {
+			outputs = append(outputs, "")
+		}
+	}
+	records := [][]string{outputs, states}
+
+	cell := func(targets []int) string {
+		if len(targets) == 0 {
+			return NoTransition
+		}
+		names := make([]string, len(targets))
+		for i, target := range targets {
+			names[i] = nfa.States[target]
+		}
+		return strings.Join(names, ",")
+	}
+
+	for symbol, symbolName := range nfa.Alphabet {
+		record := []string{symbolName}
+		for _, state := range order {
+			record = append(record, cell(nfa.Transitions[state][symbol]))
+		}
+		records = append(records, record)
+	}
+	if nfa.HasEpsilon() {
+		record := []string{EpsilonSymbol}
+		for _, state := range order {
+			record = append(record, cell(nfa.Epsilon[state]))
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func DFARecords(dfa *automata.DFA) [][]string {
+	return MooreRecords(dfa.ToMoore(FinalOutput, ""))
+}
+
+func MooreRecords(moore *automata.Moore) [][]string {
+	order := automata.StartFirst(len(moore.States), moore.Start)
+
+	outputs := []string{""}
+	states := []string{""}
+	for _, state := range order {
+		outputs = append(outputs, moore.Outputs[state])
+		states = append(states, moore.States[state])
+	}
+	records := [][]string{outputs, states}
+
+	for symbol, symbolName := range moore.Alphabet {
+		record := []string{symbolName}
+		for _, state := range order {
+			if target := moore.Transitions[state][symbol]; target != automata.NoState {
+				record = append(record, moore.States[target])
+			} else {
+				record = append(record, NoTransition)
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func MealyRecords(mealy *automata.Mealy) [][]string {
+	order := automata.StartFirst(len(mealy.States), mealy.Start)
+
+	states := []string{""}
+	for _, state := range order {
+		states = append(states, mealy.States[state])
+	}
+	records := [][]string{states}
+
+	for symbol, symbolName := range mealy.Alphabet {
+		record := []string{symbolName}
+		for _, state := range order {
+			switch transition := mealy.Transitions[state][symbol]; {
+			case transition.Target != automata.NoState:
+				record = append(record, mealy.States[transition.Target]+"/"+transition.Output)
+			case transition.HasOutput():
+				record = append(record, NoTransition+"/"+transition.Output)
+			default:
+				record = append(record, NoTransition)
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
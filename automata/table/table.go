@@ -0,0 +1,192 @@
+// Package table reads and writes the machine tables used across the
+// repository: RFC 4180 CSV with either "," or ";" as the separator.
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	Comma     = ','
+	Semicolon = ';'
+)
+
+const bom = "\xef\xbb\xbf"
+
+type Table struct {
+	Records [][]string
+	Comma   rune
+	// Positions holds where each cell of Records starts in the input, for
+	// diagnostics. File is set by ReadFile.
+	Positions [][]Position
+	File      string
+}
+
+// Position is a 1-based line and byte column.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func Read(r io.Reader) (*Table, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte(bom))
+
+	t := &Table{Comma: DetectComma(data)}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = t.Comma
+	reader.FieldsPerRecord = -1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		positions := make([]Position, len(record))
+		for i := range record {
+			positions[i].Line, positions[i].Column = reader.FieldPos(i)
+		}
+		t.Records = append(t.Records, record)
+		t.Positions = append(t.Positions, positions)
+	}
+	t.trimTrailingSeparators()
+
+	return t, nil
+}
+
+func ReadFile(filePath string) (*Table, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	t, err := Read(file)
+	if err != nil {
+		return nil, err
+	}
+	t.File = filePath
+
+	return t, nil
+}
+
+// Position returns where the cell in row and column starts. A column past
+// the end of a short row points just after its last cell.
+func (t *Table) Position(row, column int) Position {
+	if row >= len(t.Positions) || len(t.Positions[row]) == 0 {
+		return Position{}
+	}
+
+	positions, record := t.Positions[row], t.Records[row]
+	if column < len(record) {
+		return positions[column]
+	}
+	if len(record) == 0 {
+		return Position{Line: positions[0].Line, Column: 1}
+	}
+	last := positions[len(record)-1]
+	return Position{Line: last.Line, Column: last.Column + len(record[len(record)-1]) + 1}
+}
+
+// DetectComma picks the separator that occurs more often outside quotes on
+// the first non-empty line. Ties go to ";", the format our tools write.
+func DetectComma(data []byte) rune {
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		commas, semicolons := 0, 0
+		quoted := false
+		for _, char := range line {
+			switch {
+			case char == '"':
+				quoted = !quoted
+			case quoted:
+			case char == Comma:
+				commas++
+			case char == Semicolon:
+				semicolons++
+			}
+		}
+		if commas > semicolons {
+			return Comma
+		}
+		return Semicolon
+	}
+
+	return Semicolon
+}
+
+// trimTrailingSeparators drops empty cells left by a trailing separator. The
+// table width is taken from the header rows, so empty cells inside the width
+// (holes and empty outputs) are kept.
+func (t *Table) trimTrailingSeparators() {
+	width := 0
+	for i := 0; i < len(t.Records) && i < 2; i++ {
+		width = max(width, trimmedLen(t.Records[i]))
+	}
+
+	for i, record := range t.Records {
+		if len(record) > width {
+			t.Records[i] = record[:max(width, trimmedLen(record))]
+			t.Positions[i] = t.Positions[i][:max(len(t.Records[i]), 1)]
+		}
+	}
+}
+
+func trimmedLen(record []string) int {
+	n := len(record)
+	for n > 0 && record[n-1] == "" {
+		n--
+	}
+
+	return n
+}
+
+func Write(w io.Writer, records [][]string, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+func WriteFile(filePath string, records [][]string, comma rune) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = Write(file, records, comma); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// Format returns the records as text, with the error of Write if any, such as
+// csv's rejection of an invalid separator.
+func Format(records [][]string, comma rune) (string, error) {
+	var builder strings.Builder
+	if err := Write(&builder, records, comma); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
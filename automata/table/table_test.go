@@ -0,0 +1,90 @@
+package table
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// nfaInput has the shape of NFAToDFA/input.csv: comma separated, with a
+// quoted cell of several targets and empty cells for holes and outputs.
+const nfaInput = `,,,F
+,S1,S2,S3
+a,,S1,S2
+b,,S3,
+e,"S2,S3",,
+`
+
+func TestDetectComma(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want rune
+	}{
+		{"commas", nfaInput, Comma},
+		{"semicolons", ";;;F\n;S1;S2;S3\n", Semicolon},
+		{"commas inside quotes", "\"a,b,c\";x;y\n", Semicolon},
+		{"blank first line", "\n  \n,S1,S2\n", Comma},
+		{"tie", "a,b;c\n", Semicolon},
+		{"empty", "", Semicolon},
+	}
+	for _, test := range tests {
+		if got := DetectComma([]byte(test.data)); got != test.want {
+			t.Errorf("%s: DetectComma gives %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadKeepsQuotedTargetsAndHoles(t *testing.T) {
+	table, err := Read(strings.NewReader(bom + nfaInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"", "", "", "F"},
+		{"", "S1", "S2", "S3"},
+		{"a", "", "S1", "S2"},
+		{"b", "", "S3", ""},
+		{"e", "S2,S3", "", ""},
+	}
+	if table.Comma != Comma || !reflect.DeepEqual(table.Records, want) {
+		t.Errorf("Read gives %q with %q, want %q", table.Records, table.Comma, want)
+	}
+}
+
+func TestTrimTrailingSeparators(t *testing.T) {
+	table, err := Read(strings.NewReader(";;F;\n;S1;S2;\na;S2;S1;;\nb;-;;\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"", "", "F"},
+		{"", "S1", "S2"},
+		{"a", "S2", "S1"},
+		{"b", "-", ""},
+	}
+	if !reflect.DeepEqual(table.Records, want) {
+		t.Errorf("Read gives %q, want %q", table.Records, want)
+	}
+	for row, positions := range table.Positions {
+		if len(positions) != len(table.Records[row]) {
+			t.Errorf("row %d has %d positions for %d cells", row, len(positions), len(table.Records[row]))
+		}
+	}
+	if position := table.Position(2, 2); position != (Position{Line: 3, Column: 6}) {
+		t.Errorf("cell S1 of row a is at %+v", position)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	text, err := Format([][]string{{"", "S1"}, {"e", "S1,S2"}}, Comma)
+	if err != nil || text != ",S1\ne,\"S1,S2\"\n" {
+		t.Errorf("Format gives %q, %v", text, err)
+	}
+
+	if _, err := Format([][]string{{"a"}}, '"'); err == nil {
+		t.Error("Format accepts \" as the separator")
+	}
+}
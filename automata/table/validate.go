@@ -0,0 +1,172 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata/diag"
+)
+
+// Validate reports every problem of the table read as a machine of the given
+// kind, pointing at the cell it was found in. Unlike the Parse functions it
+// does not stop at the first one.
+func (t *Table) Validate(kind Kind) diag.List {
+	v := &validator{t: t}
+	if kind == KindMealy {
+		v.mealy()
+	} else {
+		v.moore(kind)
+	}
+	v.list.SetFile(t.File)
+
+	return v.list
+}
+
+type validator struct {
+	t    *Table
+	list diag.List
+}
+
+func (v *validator) errorf(row, column int, format string, args ...any) {
+	position := v.t.Position(row, column)
+	v.list.Errorf(position.Line, position.Column, format, args...)
+}
+
+func (v *validator) warnf(row, column int, format string, args ...any) {
+	position := v.t.Position(row, column)
+	v.list.Warnf(position.Line, position.Column, format, args...)
+}
+
+// states checks the row naming the states and returns the names with the
+// column each one is defined in.
+func (v *validator) states(row int, what string) map[string]int {
+	header, states := splitRow(v.t.Records[row])
+	if header != "" {
+		v.errorf(row, 0, "%s row must start with an empty cell, got %q", what, header)
+	}
+	if len(states) == 0 {
+		v.errorf(row, 1, "table has no states")
+	}
+
+	columns := make(map[string]int, len(states))
+	for i, name := range states {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			v.errorf(row, i+1, "state in column %d has no name", i+2)
+			continue
+		}
+		if first, ok := columns[name]; ok {
+			position := v.t.Position(row, first)
+			v.errorf(row, i+1, "duplicate state %q, first defined at line %d, column %d", name, position.Line, position.Column)
+			continue
+		}
+		columns[name] = i + 1
+	}
+
+	return columns
+}
+
+// inputs checks the symbol and width of the rows from first on and calls cell
+// for every transition that is not a hole.
+func (v *validator) inputs(first, statesNum int, cell func(row, column int, symbol, value string)) {
+	if first >= len(v.t.Records) {
+		v.warnf(len(v.t.Records)-1, 0, "table has no inputs")
+	}
+
+	symbols := make(map[string]int)
+	for row := first; row < len(v.t.Records); row++ {
+		symbol, cells := splitRow(v.t.Records[row])
+		if symbol == "" {
+			v.errorf(row, 0, "input row has no symbol")
+		} else if firstRow, ok := symbols[symbol]; ok {
+			v.errorf(row, 0, "duplicate input %q, first defined at line %d", symbol, v.t.Position(firstRow, 0).Line)
+		} else {
+			symbols[symbol] = row
+		}
+
+		switch {
+		case len(cells) > statesNum:
+			v.errorf(row, statesNum+1, "input %s has %d cells for %d states", symbol, len(cells), statesNum)
+		case len(cells) < statesNum:
+			v.warnf(row, len(cells)+1, "input %s has %d cells for %d states, the missing transitions are undefined", symbol, len(cells), statesNum)
+		}
+
+		for i, value := range cells[:min(len(cells), statesNum)] {
+			if !isNoTransition(value) {
+				cell(row, i+1, symbol, value)
+			}
+		}
+	}
+}
+
+func (v *validator) moore(kind Kind) {
+	records := v.t.Records
+	if len(records) < 2 {
+		v.errorf(max(len(records)-1, 0), 0, "table must have rows of outputs and states")
+		return
+	}
+
+	states := v.states(1, "states")
+	_, statesCells := splitRow(records[1])
+	statesNum := len(statesCells)
+
+	header, outputs := splitRow(records[0])
+	if header != "" {
+		v.errorf(0, 0, "outputs row must start with an empty cell, got %q", header)
+	}
+	if len(outputs) > statesNum {
+		v.errorf(0, statesNum+1, "table has %d outputs for %d states", len(outputs), statesNum)
+	}
+	for i, name := range statesCells {
+		switch {
+		case kind == KindMoore && i >= len(outputs):
+			v.errorf(0, len(outputs)+1, "state %s has no output", name)
+		case kind == KindMoore && strings.TrimSpace(outputs[i]) == "":
+			v.warnf(0, i+1, "state %s has an empty output", name)
+		case kind != KindMoore && i < len(outputs) && outputs[i] != "" && outputs[i] != FinalOutput:
+			v.errorf(0, i+1, "state %s is marked %q, only %s marks final states", name, outputs[i], FinalOutput)
+		}
+	}
+
+	v.inputs(2, statesNum, func(row, column int, symbol, value string) {
+		if kind == KindDFA && symbol == EpsilonSymbol {
+			v.errorf(row, 0, "a DFA has no %s row, read the table as an NFA", EpsilonSymbol)
+		}
+
+		targets := []string{value}
+		if kind == KindNFA {
+			targets = strings.Split(value, ",")
+		} else if strings.Contains(value, ",") {
+			v.errorf(row, column, "%s machines have one target per transition, got %q", kind, value)
+			return
+		}
+		for _, target := range targets {
+			if _, ok := states[strings.TrimSpace(target)]; !ok {
+				v.errorf(row, column, "unknown target state %q", strings.TrimSpace(target))
+			}
+		}
+	})
+}
+
+func (v *validator) mealy() {
+	if len(v.t.Records) == 0 {
+		v.list.Errorf(1, 1, "table is empty")
+		return
+	}
+
+	states := v.states(0, "states")
+	_, statesCells := splitRow(v.t.Records[0])
+
+	v.inputs(1, len(statesCells), func(row, column int, symbol, value string) {
+		target, output, ok := strings.Cut(value, "/")
+		if !ok {
+			v.errorf(row, column, "transition %q must be <state>/<output>", value)
+			return
+		}
+		if _, ok := states[strings.TrimSpace(target)]; !ok && !isNoTransition(target) {
+			v.errorf(row, column, "unknown target state %q", strings.TrimSpace(target))
+		}
+		if strings.TrimSpace(output) == "" {
+			v.errorf(row, column, "transition to %s has no output", strings.TrimSpace(target))
+		}
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/AkshachRd/automata-theory-2023/lexer
 
-go 1.21.1
+go 1.23
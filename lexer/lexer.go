@@ -1,72 +1,263 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"strconv"
 	"strings"
 )
 
+// BufferSize is the size of the buffer a Lexer reads its source through, and
+// bounds the source line its errors show.
+const BufferSize = 64 * 1024
+
 type Lexer struct {
-	text        []rune
-	pos         uint64
+	// FileName names the source in errors.
+	FileName string
+	// Recover makes Next turn illegal chars into TT_ERROR tokens and go on,
+	// and MakeTokens return all errors together as an ErrorList.
+	Recover     bool
+	reader      *bufio.Reader
+	readErr     error
+	char        rune
 	currentChar *rune
+	pos         uint64
+	line        int
+	column      int
+	// lineText holds the chars of the current line before the current char,
+	// for the source line of errors, and tokenText those of the token read.
+	lineText  []rune
+	tokenText []rune
+	keywords  map[string]struct{}
 }
 
 func NewLexer(text string) *Lexer {
-	lexer := &Lexer{text: []rune(text)}
-	lexer.currentChar = &lexer.text[lexer.pos]
+	return NewReaderLexer(strings.NewReader(text))
+}
+
+// NewLexerWithKeywords is NewLexer with its own keyword table in place of
+// KEYWORDS.
+func NewLexerWithKeywords(text string, keywords []string) *Lexer {
+	return NewReaderLexerWithKeywords(strings.NewReader(text), keywords)
+}
+
+// NewReaderLexer lexes what r yields as it is read, through a buffer of
+// BufferSize bytes, so that the source is never held in memory as a whole.
+func NewReaderLexer(r io.Reader) *Lexer {
+	return NewReaderLexerWithKeywords(r, KEYWORDS)
+}
+
+func NewReaderLexerWithKeywords(r io.Reader, keywords []string) *Lexer {
+	lexer := &Lexer{reader: bufio.NewReaderSize(r, BufferSize), line: 1, column: 1, keywords: make(map[string]struct{}, len(keywords))}
+	for _, keyword := range keywords {
+		lexer.keywords[keyword] = struct{}{}
+	}
+	lexer.readChar()
 	return lexer
 }
 
+// readChar makes the next char of the source the current one, and sets
+// currentChar to nil at the end of the source or on a read error.
+func (l *Lexer) readChar() {
+	char, _, err := l.reader.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			l.readErr = err
+		}
+		l.currentChar = nil
+		return
+	}
+
+	l.char = char
+	l.currentChar = &l.char
+}
+
 func (l *Lexer) Advance() {
-	l.pos++
-	if l.pos < uint64(len(l.text)) {
-		l.currentChar = &l.text[l.pos]
+	if l.currentChar == nil {
+		return
+	}
+
+	if *l.currentChar == '\n' {
+		l.line++
+		l.column = 1
+		l.lineText = l.lineText[:0]
 	} else {
-		l.currentChar = nil
+		l.column++
+		if len(l.lineText) < BufferSize {
+			l.lineText = append(l.lineText, *l.currentChar)
+		}
 	}
+	l.tokenText = append(l.tokenText, *l.currentChar)
+
+	l.pos++
+	l.readChar()
+}
+
+// Position returns the position of the current char.
+func (l *Lexer) Position() Position {
+	return Position{Offset: int(l.pos), Line: l.line, Column: l.column}
 }
 
+// Next reads the next token, with the positions of its first char and of the
+// char after it. It returns io.EOF after the last token and a read error of
+// the source as it is. An illegal char gives an *Error, which comes with a
+// TT_ERROR token holding the char when Recover is set.
+func (l *Lexer) Next() (Token, error) {
+	for l.currentChar != nil && strings.ContainsRune(" \t\r\n", *l.currentChar) {
+		l.Advance()
+	}
+	if l.currentChar == nil {
+		if l.readErr != nil {
+			return Token{}, l.readErr
+		}
+		return Token{}, io.EOF
+	}
+
+	var token *Token
+	var err error
+	start := l.Position()
+	l.tokenText = l.tokenText[:0]
+
+	switch {
+	case strings.ContainsRune(DIGITS, *l.currentChar):
+		token, err = l.MakeNumber()
+	case strings.ContainsRune(LETTERS, *l.currentChar):
+		token = l.MakeIdentifier()
+	case *l.currentChar == '=':
+		token = l.MakeOperator(TT_EQ, '=', TT_EE)
+	case *l.currentChar == '!':
+		token = l.MakeOperator(TT_NOT, '=', TT_NE)
+	case *l.currentChar == '<':
+		token = l.MakeOperator(TT_LT, '=', TT_LTE)
+	case *l.currentChar == '>':
+		token = l.MakeOperator(TT_GT, '=', TT_GTE)
+	case *l.currentChar == '&':
+		token, err = l.MakeDoubleOperator(TT_AND)
+	case *l.currentChar == '|':
+		token, err = l.MakeDoubleOperator(TT_OR)
+	case *l.currentChar == '+':
+		token = l.MakeChar(TT_PLUS)
+	case *l.currentChar == '-':
+		token = l.MakeChar(TT_MINUS)
+	case *l.currentChar == '*':
+		token = l.MakeChar(TT_MUL)
+	case *l.currentChar == '/':
+		token = l.MakeChar(TT_DIV)
+	case *l.currentChar == '(':
+		token = l.MakeChar(TT_LPAREN)
+	case *l.currentChar == ')':
+		token = l.MakeChar(TT_RPAREN)
+	case *l.currentChar == '{':
+		token = l.MakeChar(TT_LBRACE)
+	case *l.currentChar == '}':
+		token = l.MakeChar(TT_RBRACE)
+	case *l.currentChar == '[':
+		token = l.MakeChar(TT_LSQUARE)
+	case *l.currentChar == ']':
+		token = l.MakeChar(TT_RSQUARE)
+	case *l.currentChar == ',':
+		token = l.MakeChar(TT_COMMA)
+	case *l.currentChar == ';':
+		token = l.MakeChar(TT_SEMICOLON)
+	default:
+		char := *l.currentChar
+		l.Advance()
+		err = fmt.Errorf("illigal char: %q", char)
+	}
+	if err != nil {
+		lexErr := l.errorAt(start, err)
+		if !l.Recover {
+			return Token{}, lexErr
+		}
+		token = NewToken(TT_ERROR, string(l.tokenText))
+		err = lexErr
+	}
+
+	token.Start, token.End = start, l.Position()
+	return *token, err
+}
+
+// Tokens iterates over the tokens Next reads and their errors, up to the end
+// of the source or an error Next cannot go on after.
+func (l *Lexer) Tokens() iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		for {
+			token, err := l.Next()
+			if err == io.EOF || !yield(token, err) || !l.recovered(err) {
+				return
+			}
+		}
+	}
+}
+
+// recovered reports whether lexing goes on after err: there is none, or it
+// is an illegal char in Recover mode.
+func (l *Lexer) recovered(err error) bool {
+	var lexErr *Error
+	return err == nil || l.Recover && errors.As(err, &lexErr)
+}
+
+// MakeTokens reads every token of the source. It stops at the first error
+// unless Recover is set.
 func (l *Lexer) MakeTokens() ([]*Token, error) {
 	var tokens []*Token
+	var errs ErrorList
 
-	for l.currentChar != nil {
-		switch {
-		case strings.ContainsRune(" \t", *l.currentChar):
-			l.Advance()
-		case strings.ContainsRune(DIGITS, *l.currentChar):
-			token, err := l.MakeNumber()
-			if err != nil {
-				return make([]*Token, 0), err
-			}
+	for token, err := range l.Tokens() {
+		if !l.recovered(err) {
+			return make([]*Token, 0), err
+		}
 
-			tokens = append(tokens, token)
-		case *l.currentChar == '+':
-			tokens = append(tokens, NewToken(TT_PLUS))
-			l.Advance()
-		case *l.currentChar == '-':
-			tokens = append(tokens, NewToken(TT_MINUS))
-			l.Advance()
-		case *l.currentChar == '*':
-			tokens = append(tokens, NewToken(TT_MUL))
-			l.Advance()
-		case *l.currentChar == '/':
-			tokens = append(tokens, NewToken(TT_DIV))
-			l.Advance()
-		case *l.currentChar == '(':
-			tokens = append(tokens, NewToken(TT_LPAREN))
-			l.Advance()
-		case *l.currentChar == ')':
-			tokens = append(tokens, NewToken(TT_RPAREN))
-			l.Advance()
-		default:
-			char := l.currentChar
-			l.Advance()
-			return make([]*Token, 0), fmt.Errorf("illigal char: %q", *char)
+		var lexErr *Error
+		if errors.As(err, &lexErr) {
+			errs = append(errs, lexErr)
 		}
+		tokens = append(tokens, &token)
 	}
 
-	return tokens, nil
+	return tokens, errs.Err()
+}
+
+// MakeChar reads a token of a single char.
+func (l *Lexer) MakeChar(tokenType string) *Token {
+	l.Advance()
+	return NewToken(tokenType)
+}
+
+// errorAt turns err into an Error at start, a position on the current line,
+// with that line as far as it fits the buffer.
+func (l *Lexer) errorAt(start Position, err error) *Error {
+	lexErr := &Error{FileName: l.FileName, Pos: start, Message: err.Error()}
+	if start.Line == l.line && start.Column <= len(l.lineText)+1 {
+		lexErr.Line = string(l.lineText) + l.restOfLine()
+	}
+
+	return lexErr
+}
+
+// restOfLine returns the current char and those after it up to the end of
+// its line, peeking at the source without reading past the buffer.
+func (l *Lexer) restOfLine() string {
+	if l.currentChar == nil || *l.currentChar == '\n' {
+		return ""
+	}
+
+	n := l.reader.Buffered()
+	for {
+		peeked, err := l.reader.Peek(n)
+		if i := bytes.IndexByte(peeked, '\n'); i != -1 {
+			peeked = peeked[:i]
+		} else if err == nil && n < BufferSize {
+			n = min(max(n+1, l.reader.Buffered()), BufferSize)
+			continue
+		}
+
+		return strings.TrimSuffix(string(*l.currentChar)+string(peeked), "\r")
+	}
 }
 
 func (l *Lexer) MakeNumber() (*Token, error) {
@@ -94,3 +285,46 @@ func (l *Lexer) MakeNumber() (*Token, error) {
 	num, err := strconv.ParseFloat(numStr, 32)
 	return NewToken(TT_FLOAT, float32(num)), err
 }
+
+// MakeIdentifier reads the longest run of letters, digits and underscores,
+// which is a keyword when the keyword table has it.
+func (l *Lexer) MakeIdentifier() *Token {
+	var builder strings.Builder
+
+	for l.currentChar != nil && strings.ContainsRune(LETTERS+DIGITS, *l.currentChar) {
+		builder.WriteRune(*l.currentChar)
+		l.Advance()
+	}
+
+	idStr := builder.String()
+
+	if _, ok := l.keywords[idStr]; ok {
+		return NewToken(TT_KEYWORD, idStr)
+	}
+	return NewToken(TT_IDENTIFIER, idStr)
+}
+
+// MakeOperator reads an operator of one char, or of two when the second one
+// is next, as the longest match wins: <= is a single LTE token.
+func (l *Lexer) MakeOperator(singleType string, next rune, doubleType string) *Token {
+	l.Advance()
+	if l.currentChar != nil && *l.currentChar == next {
+		l.Advance()
+		return NewToken(doubleType)
+	}
+
+	return NewToken(singleType)
+}
+
+// MakeDoubleOperator reads an operator made of the current char twice, like
+// && and ||, which has no one char form.
+func (l *Lexer) MakeDoubleOperator(tokenType string) (*Token, error) {
+	char := *l.currentChar
+	l.Advance()
+	if l.currentChar == nil || *l.currentChar != char {
+		return nil, fmt.Errorf("illigal char: %q, expected %q", char, string([]rune{char, char}))
+	}
+
+	l.Advance()
+	return NewToken(tokenType), nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lex reads every token of text, failing the test on an error.
+func lex(t *testing.T, text string) []*Token {
+	t.Helper()
+	tokens, err := NewLexer(text).MakeTokens()
+	if err != nil {
+		t.Fatalf("%q: %v", text, err)
+	}
+	return tokens
+}
+
+func types(tokens []*Token) []string {
+	result := make([]string, len(tokens))
+	for i, token := range tokens {
+		result[i] = token.Type
+	}
+	return result
+}
+
+func TestOperators(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"=", []string{TT_EQ}},
+		{"==", []string{TT_EE}},
+		{"===", []string{TT_EE, TT_EQ}},
+		{"= =", []string{TT_EQ, TT_EQ}},
+		{"!", []string{TT_NOT}},
+		{"!=", []string{TT_NE}},
+		{"!!", []string{TT_NOT, TT_NOT}},
+		{"<", []string{TT_LT}},
+		{"<=", []string{TT_LTE}},
+		{"<<=", []string{TT_LT, TT_LTE}},
+		{">", []string{TT_GT}},
+		{">=", []string{TT_GTE}},
+		{"&&", []string{TT_AND}},
+		{"||", []string{TT_OR}},
+		{"&&&&", []string{TT_AND, TT_AND}},
+		{"+-*/", []string{TT_PLUS, TT_MINUS, TT_MUL, TT_DIV}},
+		{"(){}[],;", []string{TT_LPAREN, TT_RPAREN, TT_LBRACE, TT_RBRACE, TT_LSQUARE, TT_RSQUARE, TT_COMMA, TT_SEMICOLON}},
+		{"x<=1", []string{TT_IDENTIFIER, TT_LTE, TT_INT}},
+		{"!x&&y", []string{TT_NOT, TT_IDENTIFIER, TT_AND, TT_IDENTIFIER}},
+	}
+
+	for _, tt := range tests {
+		if got := types(lex(t, tt.text)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: tokens %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSingleAmpersandAndBar(t *testing.T) {
+	for _, text := range []string{"&", "a & b", "|", "a | b"} {
+		if _, err := NewLexer(text).MakeTokens(); err == nil {
+			t.Errorf("%q: no error", text)
+		}
+	}
+}
+
+func TestIdentifiersAndKeywords(t *testing.T) {
+	tokens := lex(t, "var x_1 = if1 + 2.5")
+	want := []Token{
+		{Type: TT_KEYWORD, Value: "var"},
+		{Type: TT_IDENTIFIER, Value: "x_1"},
+		{Type: TT_EQ},
+		{Type: TT_IDENTIFIER, Value: "if1"},
+		{Type: TT_PLUS},
+		{Type: TT_FLOAT, Value: float32(2.5)},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("tokens %v", types(tokens))
+	}
+	for i, token := range tokens {
+		if token.Type != want[i].Type || token.Value != want[i].Value {
+			t.Errorf("token %d: %s %v, want %s %v", i, token.Type, token.Value, want[i].Type, want[i].Value)
+		}
+	}
+
+	tokens, err := NewLexerWithKeywords("var let", []string{"let"}).MakeTokens()
+	if err != nil || !reflect.DeepEqual(types(tokens), []string{TT_IDENTIFIER, TT_KEYWORD}) {
+		t.Errorf("own keywords: tokens %v, %v", types(tokens), err)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	tokens := lex(t, "x =\n  10 <=\r\n\tyz")
+	want := []struct{ start, end Position }{
+		{Position{0, 1, 1}, Position{1, 1, 2}},
+		{Position{2, 1, 3}, Position{3, 1, 4}},
+		{Position{6, 2, 3}, Position{8, 2, 5}},
+		{Position{9, 2, 6}, Position{11, 2, 8}},
+		{Position{14, 3, 2}, Position{16, 3, 4}},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("tokens %v", types(tokens))
+	}
+	for i, token := range tokens {
+		if token.Start != want[i].start || token.End != want[i].end {
+			t.Errorf("token %d %s: %v-%v, want %v-%v", i, token.Type, token.Start, token.End, want[i].start, want[i].end)
+		}
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	lexer := NewLexer("x = 1\n\ty = $ + 2\nz")
+	lexer.FileName = "input.txt"
+	_, err := lexer.MakeTokens()
+
+	want := "input.txt:2:6: illigal char: '$'\n\ty = $ + 2\n\t    ^"
+	if err == nil || err.Error() != want {
+		t.Errorf("error %q, want %q", err, want)
+	}
+
+	_, err = NewLexer("a & b").MakeTokens()
+	want = "<input>:1:3: illigal char: '&', expected \"&&\"\na & b\n  ^"
+	if err == nil || err.Error() != want {
+		t.Errorf("error %q, want %q", err, want)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	lexer := NewLexer("x = $1\n@ & y;")
+	lexer.Recover = true
+	tokens, err := lexer.MakeTokens()
+
+	want := []string{TT_IDENTIFIER, TT_EQ, TT_ERROR, TT_INT, TT_ERROR, TT_ERROR, TT_IDENTIFIER, TT_SEMICOLON}
+	if got := types(tokens); !reflect.DeepEqual(got, want) {
+		t.Fatalf("tokens %v, want %v", got, want)
+	}
+	if tokens[2].Value != "$" || tokens[4].Value != "@" || tokens[5].Value != "&" {
+		t.Errorf("error tokens %v, %v, %v", tokens[2].Value, tokens[4].Value, tokens[5].Value)
+	}
+
+	var errs ErrorList
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("error %v, want a list of three", err)
+	}
+	for i, pos := range []Position{{4, 1, 5}, {7, 2, 1}, {9, 2, 3}} {
+		if errs[i].Pos != pos {
+			t.Errorf("error %d at %v, want %v", i, errs[i].Pos, pos)
+		}
+	}
+}
+
+func TestRecoverWithoutErrors(t *testing.T) {
+	lexer := NewLexer("a && b")
+	lexer.Recover = true
+	tokens, err := lexer.MakeTokens()
+	if err != nil || len(tokens) != 3 {
+		t.Errorf("tokens %v, %v", types(tokens), err)
+	}
+}
+
+func TestStopsAtFirstError(t *testing.T) {
+	tokens, err := NewLexer("$ @").MakeTokens()
+	var lexErr *Error
+	if len(tokens) != 0 || !errors.As(err, &lexErr) || lexErr.Pos.Column != 1 {
+		t.Errorf("tokens %v, error %v", types(tokens), err)
+	}
+}
+
+func TestEmptyInput(t *testing.T) {
+	for _, text := range []string{"", " \n\t\r\n"} {
+		tokens, err := NewLexer(text).MakeTokens()
+		if err != nil || len(tokens) != 0 {
+			t.Errorf("%q: tokens %v, %v", text, types(tokens), err)
+		}
+		if _, err = NewLexer(text).Next(); err != io.EOF {
+			t.Errorf("%q: Next returns %v, want io.EOF", text, err)
+		}
+	}
+}
+
+func TestTokenAcrossBufferBoundary(t *testing.T) {
+	// The identifier starts 3 bytes before the end of the first buffer.
+	name := strings.Repeat("a", 10)
+	text := strings.Repeat(" ", BufferSize-3) + name + " <= 1"
+	tokens := lex(t, text)
+
+	if len(tokens) != 3 || tokens[0].Value != name || tokens[1].Type != TT_LTE {
+		t.Fatalf("tokens %v", types(tokens))
+	}
+	if start := (Position{BufferSize - 3, 1, BufferSize - 2}); tokens[0].Start != start {
+		t.Errorf("start %v, want %v", tokens[0].Start, start)
+	}
+	if end := BufferSize - 3 + len(name); tokens[0].End.Offset != end {
+		t.Errorf("end offset %d, want %d", tokens[0].End.Offset, end)
+	}
+
+	// So does an operator of two chars.
+	text = strings.Repeat(" ", BufferSize-1) + "!= x"
+	if got := types(lex(t, text)); !reflect.DeepEqual(got, []string{TT_NE, TT_IDENTIFIER}) {
+		t.Errorf("tokens %v", got)
+	}
+}
+
+// oneByteReader hands out its source one byte per Read, as a slow stream.
+type oneByteReader struct {
+	source string
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if r.source == "" {
+		return 0, io.EOF
+	}
+	n := copy(p[:1], r.source)
+	r.source = r.source[n:]
+	return n, nil
+}
+
+func TestNextStreams(t *testing.T) {
+	lexer := NewReaderLexer(&oneByteReader{"if x >= 10 { y }"})
+
+	var got []string
+	for {
+		token, err := lexer.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, token.Type)
+	}
+	want := []string{TT_KEYWORD, TT_IDENTIFIER, TT_GTE, TT_INT, TT_LBRACE, TT_IDENTIFIER, TT_RBRACE}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens %v, want %v", got, want)
+	}
+	if _, err := lexer.Next(); err != io.EOF {
+		t.Errorf("Next after the end returns %v, want io.EOF", err)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	var got []string
+	for token, err := range NewLexer("a b c d").Tokens() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, token.Value.(string))
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("tokens %v", got)
+	}
+
+	count := 0
+	for _, err := range NewLexer("a $ b").Tokens() {
+		count++
+		if count == 2 && err == nil {
+			t.Errorf("no error for $")
+		}
+	}
+	if count != 2 {
+		t.Errorf("%d tokens, want to stop at the error", count)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk on fire")
+}
+
+func TestReadError(t *testing.T) {
+	_, err := NewReaderLexer(io.MultiReader(strings.NewReader("x "), failingReader{})).MakeTokens()
+	if err == nil || err.Error() != "disk on fire" {
+		t.Errorf("error %v, want the read error", err)
+	}
+}
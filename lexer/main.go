@@ -2,19 +2,36 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
 )
 
+// main tokenizes the file given as the argument, printing every token with
+// its span, or else runs a REPL on stdin. Both report every illegal char and
+// go on.
 func main() {
+	if len(os.Args) > 1 {
+		if err := lexFile(os.Args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
 		fmt.Printf("Lexer > ")
 
 		response, err := reader.ReadString('\n')
+		if err == io.EOF {
+			fmt.Printf("\n")
+			return
+		}
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -22,26 +39,72 @@ func main() {
 		response = strings.ToLower(strings.TrimSpace(response))
 
 		lexer := NewLexer(response)
+		lexer.FileName = "<stdin>"
+		lexer.Recover = true
 		tokens, err := lexer.MakeTokens()
-		if err != nil {
-			log.Fatal(err)
-		}
 
 		for _, token := range tokens {
 			PrintToken(token)
 		}
 		fmt.Printf("\n")
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// lexFile prints the tokens of a file as they are read, so that files of any
+// size take little memory, and every error on stderr.
+func lexFile(fileName string) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
 	}
+	defer file.Close()
+
+	lexer := NewReaderLexer(file)
+	lexer.FileName = fileName
+	lexer.Recover = true
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	errorsCount := 0
+	for token, err := range lexer.Tokens() {
+		var lexErr *Error
+		if err != nil && !errors.As(err, &lexErr) {
+			return err
+		}
+
+		fmt.Fprintf(writer, "%s-%s %s\n", token.Start, token.End, FormatToken(&token))
+		if lexErr != nil {
+			writer.Flush()
+			fmt.Fprintln(os.Stderr, lexErr)
+			errorsCount++
+		}
+	}
+
+	if errorsCount > 0 {
+		return fmt.Errorf("%s: illegal chars: %d", fileName, errorsCount)
+	}
+	return nil
 }
 
 func PrintToken(token *Token) {
-	fmt.Printf("%s", token.Type)
+	fmt.Printf("%s, ", FormatToken(token))
+}
+
+// FormatToken renders a token as its type and, for numbers and names, its
+// value after a colon.
+func FormatToken(token *Token) string {
+	text := token.Type
 	if token.Value != nil {
 		if f, ok := token.Value.(float32); ok {
-			fmt.Printf(":%f", f)
+			text += fmt.Sprintf(":%f", f)
 		} else if i, ok := token.Value.(int); ok {
-			fmt.Printf(":%d", i)
+			text += fmt.Sprintf(":%d", i)
+		} else if s, ok := token.Value.(string); ok {
+			text += ":" + s
 		}
 	}
-	fmt.Printf(", ")
+	return text
 }
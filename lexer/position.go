@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a place in the source: the offset in runes from its start and
+// the line and column, both counted from 1.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Error is a lexing error at a position of the source. It prints as
+// file:line:col followed by the source line and a caret under the column.
+type Error struct {
+	FileName string
+	Pos      Position
+	Message  string
+	// Line is the source line of Pos, without its newline, or empty when it
+	// is too long to keep.
+	Line string
+}
+
+func (e *Error) Error() string {
+	fileName := e.FileName
+	if fileName == "" {
+		fileName = "<input>"
+	}
+
+	if e.Line == "" {
+		return fmt.Sprintf("%s:%s: %s", fileName, e.Pos, e.Message)
+	}
+	return fmt.Sprintf("%s:%s: %s\n%s\n%s", fileName, e.Pos, e.Message, e.Line, caret(e.Line, e.Pos.Column))
+}
+
+// caret points at a column of line, keeping the tabs before it so that the
+// caret lines up under the char.
+func caret(line string, column int) string {
+	var indent strings.Builder
+	for i, char := range []rune(line) {
+		if i >= column-1 {
+			break
+		}
+		if char == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+
+	return indent.String() + "^"
+}
+
+// ErrorList holds every error of a recovering lexer in source order.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, err := range l {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Err returns the list as an error, or nil when it is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
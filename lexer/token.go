@@ -1,19 +1,49 @@
 package main
 
 const DIGITS = "0123456789"
+const LETTERS = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_"
+
+// KEYWORDS are the words NewLexer makes TT_KEYWORD tokens of instead of
+// TT_IDENTIFIER ones.
+var KEYWORDS = []string{"var", "if", "else", "while", "for", "func", "return", "true", "false"}
 
 const TT_INT = "INT"
 const TT_FLOAT = "FLOAT"
+const TT_IDENTIFIER = "IDENTIFIER"
+const TT_KEYWORD = "KEYWORD"
 const TT_PLUS = "PLUS"
 const TT_MINUS = "MINUS"
 const TT_MUL = "MUL"
 const TT_DIV = "DIV"
+const TT_EQ = "EQ"
+const TT_EE = "EE"
+const TT_NE = "NE"
+const TT_LT = "LT"
+const TT_LTE = "LTE"
+const TT_GT = "GT"
+const TT_GTE = "GTE"
+const TT_AND = "AND"
+const TT_OR = "OR"
+const TT_NOT = "NOT"
+const TT_COMMA = "COMMA"
+const TT_SEMICOLON = "SEMICOLON"
 const TT_LPAREN = "LPAREN"
 const TT_RPAREN = "RPAREN"
+const TT_LBRACE = "LBRACE"
+const TT_RBRACE = "RBRACE"
+const TT_LSQUARE = "LSQUARE"
+const TT_RSQUARE = "RSQUARE"
+
+// TT_ERROR holds the illegal text a recovering lexer skipped.
+const TT_ERROR = "ERROR"
 
 type Token struct {
 	Type  string
 	Value interface{}
+	// Start is the position of the first char of the token and End that of
+	// the char after it.
+	Start Position
+	End   Position
 }
 
 func NewToken(tokenType string, value ...interface{}) *Token {
@@ -21,5 +51,5 @@ func NewToken(tokenType string, value ...interface{}) *Token {
 	if len(value) > 0 {
 		val = value[0]
 	}
-	return &Token{tokenType, val}
+	return &Token{Type: tokenType, Value: val}
 }
@@ -1,3 +1,7 @@
 module github.com/AkshachRd/automata-theory-2023/minimization
 
 go 1.21.1
+
+require github.com/AkshachRd/automata-theory-2023/automata v0.0.0
+
+replace github.com/AkshachRd/automata-theory-2023/automata => ../automata
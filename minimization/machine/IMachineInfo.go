@@ -1,5 +1,5 @@
 package machine
 
 type IMachineInfo interface {
-	GetCsvData() string
+	GetCsvData() (string, error)
 }
\ No newline at end of file
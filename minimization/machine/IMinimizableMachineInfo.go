@@ -1,6 +1,15 @@
 package machine
 
+import (
+	"io"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+)
+
 type IMinimizableMachineInfo interface {
 	IMachineInfo
-	Minimize()
+	Minimize() error
+	MinimizeWith(algorithm automata.Algorithm) error
+	Explain(w io.Writer, format explain.Format) error
 }
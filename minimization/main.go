@@ -1,13 +1,18 @@
 package main
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"github.com/AkshachRd/automata-theory-2023/automata/kiss"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
 	"github.com/AkshachRd/automata-theory-2023/minimization/machine"
 	"github.com/AkshachRd/automata-theory-2023/minimization/mealy"
 	"github.com/AkshachRd/automata-theory-2023/minimization/moore"
@@ -22,6 +27,9 @@ type Args struct {
     ConversionType      string
     SourceFilePath      string
     DestinationFilePath string
+    Algorithm           string
+    ExplainFilePath     string
+    SearchLimit         int
 }
 
 var AvailableConversionTypes = map[string]struct{}{
@@ -29,40 +37,71 @@ var AvailableConversionTypes = map[string]struct{}{
 	MOORE_MINIMIZATION_TYPE: {},
 }
 
-func NewArgs(conversionType, sourceFilePath, destinationFilePath string) (*Args, error) {
+func NewArgs(conversionType, sourceFilePath, destinationFilePath, algorithm string) (*Args, error) {
     if _, ok := AvailableConversionTypes[strings.ToLower(conversionType)]; !ok {
         return nil, errors.New("incorrect conversion type")
     }
+    if algorithm != "" {
+        if _, err := automata.ParseAlgorithm(strings.ToLower(algorithm)); err != nil {
+            return nil, err
+        }
+    }
 
     return &Args{
         ConversionType:      conversionType,
         SourceFilePath:      sourceFilePath,
         DestinationFilePath: destinationFilePath,
+        Algorithm:           algorithm,
+        SearchLimit:         automata.DefaultSearchLimit,
     }, nil
 }
 
+const (
+    EXPLAIN_ARG_PREFIX      = "--explain="
+    SEARCH_LIMIT_ARG_PREFIX = "--search-limit="
+)
+
+// ParseArgs accepts <mealy|moore> <source> <destination> [refinement|hopcroft|brzozowski].
+// Without the algorithm partition refinement is used. --explain=<file>
+// anywhere among them also writes its k-equivalence partitions to the file, as
+// HTML for .html and Markdown otherwise. --search-limit=<n> bounds the search
+// for the smallest cover of an incompletely specified mealy machine, 0 keeping
+// the greedy one.
 func ParseArgs(args []string) (*Args, error) {
-    if len(args) != 3 {
-        return nil, errors.New("incorrect arguments count")
+    explainFilePath := ""
+    searchLimit := automata.DefaultSearchLimit
+    positionalArgs := make([]string, 0, len(args))
+    for _, arg := range args {
+        if strings.HasPrefix(arg, EXPLAIN_ARG_PREFIX) {
+            explainFilePath = strings.TrimPrefix(arg, EXPLAIN_ARG_PREFIX)
+        } else if strings.HasPrefix(arg, SEARCH_LIMIT_ARG_PREFIX) {
+            limit, err := strconv.Atoi(strings.TrimPrefix(arg, SEARCH_LIMIT_ARG_PREFIX))
+            if err != nil || limit < 0 {
+                return nil, errors.New("incorrect search limit")
+            }
+            searchLimit = limit
+        } else {
+            positionalArgs = append(positionalArgs, arg)
+        }
     }
 
-    return NewArgs(args[0], args[1], args[2])
-}
-
-func GetInfoFromFile(filePath string) ([]string, error) {
-    file, err := os.Open(filePath)
+    var parsedArgs *Args
+    var err error
+    switch len(positionalArgs) {
+    case 3:
+        parsedArgs, err = NewArgs(positionalArgs[0], positionalArgs[1], positionalArgs[2], "")
+    case 4:
+        parsedArgs, err = NewArgs(positionalArgs[0], positionalArgs[1], positionalArgs[2], positionalArgs[3])
+    default:
+        return nil, errors.New("incorrect arguments count")
+    }
     if err != nil {
         return nil, err
     }
-    defer file.Close()
-
-    var lines []string
-    scanner := bufio.NewScanner(file)
-    for scanner.Scan() {
-        lines = append(lines, scanner.Text())
-    }
 
-    return lines, scanner.Err()
+    parsedArgs.ExplainFilePath = explainFilePath
+    parsedArgs.SearchLimit = searchLimit
+    return parsedArgs, nil
 }
 
 func PrintDataToFile(data, filePath string) error {
@@ -85,26 +124,136 @@ func PrintDataToFile(data, filePath string) error {
     return nil
 }
 
-func ProcessData(infoFromFile []string, conversionType string) (machine.IMachineInfo, error) {
-    var machineInfo machine.IMachineInfo
+// IsKissFile reports whether the file holds a KISS2 benchmark rather than a
+// table, judging by its .kiss or .kiss2 extension.
+func IsKissFile(filePath string) bool {
+    extension := strings.ToLower(filepath.Ext(filePath))
+    return extension == ".kiss" || extension == ".kiss2"
+}
+
+// ReadRecords reads a KISS2 benchmark or a table. Tables are validated
+// first, and every problem found is returned with its line and column.
+func ReadRecords(filePath, conversionType string) ([][]string, error) {
+    if IsKissFile(filePath) {
+        mealyMachine, err := kiss.ReadFile(filePath)
+        if err != nil {
+            return nil, err
+        }
+        return table.MealyRecords(mealyMachine), nil
+    }
 
+    sourceTable, err := table.ReadFile(filePath)
+    if err != nil {
+        return nil, err
+    }
+
+    diagnostics := sourceTable.Validate(table.Kind(strings.ToLower(conversionType)))
+    diagnostics.Sort()
+    if err = diagnostics.Err(); err != nil {
+        return nil, err
+    }
+    return sourceTable.Records, nil
+}
+
+func PrintMachineToFile(machineInfo machine.IMachineInfo, filePath string) error {
+    if !IsKissFile(filePath) {
+        data, err := machineInfo.GetCsvData()
+        if err != nil {
+            return err
+        }
+        return PrintDataToFile(data, filePath)
+    }
+
+    mealyMachineInfo, ok := machineInfo.(*mealy.MealyMachineInfo)
+    if !ok {
+        return errors.New("only mealy machines can be written to KISS2")
+    }
+    mealyMachine, err := mealyMachineInfo.ToMealy()
+    if err != nil {
+        return err
+    }
+    return kiss.WriteFile(filePath, mealyMachine)
+}
+
+func NewMachineInfo(records [][]string, conversionType string) (machine.IMinimizableMachineInfo, error) {
     switch strings.ToLower(conversionType) {
     case MEALY_MINIMIZATION_TYPE:
-        mealyMachineInfo, err := mealy.NewMealyMachineInfo(infoFromFile)
+        mealyMachineInfo, err := mealy.NewMealyMachineInfo(records)
 		if err != nil {
 			return nil, err
 		}
-        mealyMachineInfo.Minimize()
-        machineInfo = mealyMachineInfo
+        return mealyMachineInfo, nil
     case MOORE_MINIMIZATION_TYPE:
-        mooreMachineInfo, err := moore.NewMooreMachineInfo(infoFromFile)
+        mooreMachineInfo, err := moore.NewMooreMachineInfo(records)
 		if err != nil {
 			return nil, err
 		}
-        mooreMachineInfo.Minimize()
-        machineInfo = mooreMachineInfo
-    default:
-        return nil, errors.New("unavailable conversion type")
+        return mooreMachineInfo, nil
+    }
+
+    return nil, errors.New("unavailable conversion type")
+}
+
+// ExplainToFile writes the steps of ProcessData to the file; searchLimit
+// bounds the cover search of an incompletely specified mealy machine as there.
+func ExplainToFile(records [][]string, conversionType, filePath string, searchLimit int) error {
+    machineInfo, err := NewMachineInfo(records, conversionType)
+    if err != nil {
+        return err
+    }
+
+    file, err := os.Create(filePath)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    if mealyMachineInfo, ok := machineInfo.(*mealy.MealyMachineInfo); ok {
+        return mealyMachineInfo.ExplainReduce(file, explain.FormatOf(filePath), searchLimit)
+    }
+    return machineInfo.Explain(file, explain.FormatOf(filePath))
+}
+
+// ProcessData minimizes the machine, by partition refinement when no
+// algorithm is given. Without an algorithm an incompletely specified mealy
+// machine is reduced by compatible states instead, and whether the result is
+// proven minimal is printed.
+func ProcessData(records [][]string, conversionType, algorithmName string, searchLimit int) (machine.IMachineInfo, error) {
+    machineInfo, err := NewMachineInfo(records, conversionType)
+    if err != nil {
+        return nil, err
+    }
+
+    mealyMachineInfo, isMealy := machineInfo.(*mealy.MealyMachineInfo)
+    incompletelySpecified := false
+    if isMealy && algorithmName == "" {
+        incompletelySpecified, err = mealyMachineInfo.IsIncompletelySpecified()
+        if err != nil {
+            return nil, err
+        }
+    }
+    if incompletelySpecified {
+        reduction, err := mealyMachineInfo.Reduce(searchLimit)
+        if err != nil {
+            return nil, err
+        }
+        if reduction.Exact {
+            fmt.Printf("reduced to %d states, no closed cover is smaller\n", len(reduction.Cover))
+        } else {
+            fmt.Printf("reduced to %d states, the search for a smaller closed cover reached its limit\n", len(reduction.Cover))
+        }
+        return machineInfo, nil
+    }
+
+    algorithm := automata.Refinement
+    if algorithmName != "" {
+        algorithm, err = automata.ParseAlgorithm(strings.ToLower(algorithmName))
+        if err != nil {
+            return nil, err
+        }
+    }
+    if err = machineInfo.MinimizeWith(algorithm); err != nil {
+        return nil, err
     }
 
     return machineInfo, nil
@@ -118,19 +267,27 @@ func main() {
         return
     }
 
-    infoFromFile, err := GetInfoFromFile(parsedArgs.SourceFilePath)
+    records, err := ReadRecords(parsedArgs.SourceFilePath, parsedArgs.ConversionType)
     if err != nil {
         fmt.Println(err)
         return
     }
 
-    machineInfo, err := ProcessData(infoFromFile, parsedArgs.ConversionType)
+    if parsedArgs.ExplainFilePath != "" {
+        err = ExplainToFile(records, parsedArgs.ConversionType, parsedArgs.ExplainFilePath, parsedArgs.SearchLimit)
+        if err != nil {
+            fmt.Println(err)
+            return
+        }
+    }
+
+    machineInfo, err := ProcessData(records, parsedArgs.ConversionType, parsedArgs.Algorithm, parsedArgs.SearchLimit)
     if err != nil {
         fmt.Println(err)
         return
     }
 
-    err = PrintDataToFile(machineInfo.GetCsvData(), parsedArgs.DestinationFilePath)
+    err = PrintMachineToFile(machineInfo, parsedArgs.DestinationFilePath)
     if err != nil {
         fmt.Println(err)
         return
@@ -2,10 +2,15 @@ package mealy
 
 import (
 	"errors"
+	"io"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
 )
 
 type MealyMachineInfo struct {
@@ -14,24 +19,22 @@ type MealyMachineInfo struct {
 	TransitionFunctions [][]string
 }
 
-func NewMealyMachineInfo(info []string) (*MealyMachineInfo, error) {
+func NewMealyMachineInfo(records [][]string) (*MealyMachineInfo, error) {
 	m := &MealyMachineInfo{
 		States:              make([]string, 0),
 		InnerStates:         make([]string, 0),
 		TransitionFunctions: make([][]string, 0),
 	}
 
-	if len(info) == 0 {
+	if len(records) == 0 {
 		return m, nil
 	}
 
-	dirtyStates := strings.Split(info[0], ";")
-	m.States = append(m.States, dirtyStates[1:]...)
+	m.States = append(m.States, records[0][1:]...)
 
-	for i := 1; i < len(info); i++ {
-		values := strings.Split(info[i], ";")
-		m.InnerStates = append(m.InnerStates, values[0])
-		m.TransitionFunctions = append(m.TransitionFunctions, values[1:])
+	for i := 1; i < len(records); i++ {
+		m.InnerStates = append(m.InnerStates, records[i][0])
+		m.TransitionFunctions = append(m.TransitionFunctions, records[i][1:])
 	}
 
 	if len(m.States) <= 1 {
@@ -41,28 +44,34 @@ func NewMealyMachineInfo(info []string) (*MealyMachineInfo, error) {
 	return m, nil
 }
 
-func (m *MealyMachineInfo) GetCsvData() string {
+func (m *MealyMachineInfo) GetRecords() [][]string {
 	sort.Strings(m.States)
 
-	csvData := ";"
-	csvData += strings.Join(m.States, ";")
-	csvData += "\n"
+	records := make([][]string, 0, len(m.InnerStates)+1)
+	records = append(records, append([]string{""}, m.States...))
 
 	for i := 0; i < len(m.InnerStates); i++ {
-		csvData += m.InnerStates[i] + ";"
-		for j := 0; j < len(m.TransitionFunctions[i]); j++ {
-            csvData += m.TransitionFunctions[i][j]
-            if j != len(m.TransitionFunctions[i])-1 {
-                csvData += ";"
-            }
-        }
-		csvData += "\n"
+		records = append(records, append([]string{m.InnerStates[i]}, m.TransitionFunctions[i]...))
 	}
 
-	return csvData
+	return records
 }
 
-func (m *MealyMachineInfo) Minimize() {
+func (m *MealyMachineInfo) GetCsvData() (string, error) {
+	return table.Format(m.GetRecords(), table.Semicolon)
+}
+
+// Minimize merges equivalent states. An incompletely specified machine, with
+// - for a next state or an output, is reduced by Reduce instead.
+func (m *MealyMachineInfo) Minimize() error {
+    incompletelySpecified, err := m.IsIncompletelySpecified()
+    if err != nil {
+        return err
+    }
+    if incompletelySpecified {
+        _, err = m.Reduce(automata.DefaultSearchLimit)
+        return err
+    }
     m.deleteUnreachableStates()
     previousMatchingMinimizedStatesToStates := make(map[string]map[string]struct{})
     matchingMinimizedStatesToStates := make(map[string]map[string]struct{})
@@ -90,6 +99,72 @@ func (m *MealyMachineInfo) Minimize() {
     minimizedTransitionFunctions := m.getMinimizedTransitionFunctions(matchingMinimizedStatesToStates)
     m.States = minimizedStates
     m.TransitionFunctions = minimizedTransitionFunctions
+
+    return nil
+}
+
+func (m *MealyMachineInfo) MinimizeWith(algorithm automata.Algorithm) error {
+	if algorithm == automata.Brzozowski {
+		return errors.New("brzozowski minimization works on acceptors only, not on mealy machines")
+	}
+
+	machine, err := m.ToMealy()
+	if err != nil {
+		return err
+	}
+
+	*m = *NewMealyMachineInfoFromMealy(automata.MinimizeMealyWith(machine, algorithm))
+
+	return nil
+}
+
+// Reduce replaces the machine with the one of a closed cover of compatible
+// states, treating - next states and outputs as don't-cares. The search for
+// the smallest cover is bounded by limit, see automata.ReduceMealy.
+func (m *MealyMachineInfo) Reduce(limit int) (*automata.Reduction, error) {
+	machine, err := m.ToMealy()
+	if err != nil {
+		return nil, err
+	}
+
+	reduction := automata.ReduceMealy(machine, limit)
+	*m = *NewMealyMachineInfoFromMealy(reduction.Machine)
+
+	return reduction, nil
+}
+
+// IsIncompletelySpecified reports whether some next state or output of the
+// machine is -, so that it has to be reduced by Reduce rather than minimized.
+// A table that cannot be read is an error rather than a complete machine.
+func (m *MealyMachineInfo) IsIncompletelySpecified() (bool, error) {
+	machine, err := m.ToMealy()
+	if err != nil {
+		return false, err
+	}
+
+	return !machine.IsSpecified(), nil
+}
+
+// Explain writes the k-equivalence partitions that MinimizeWith goes through,
+// with the input that splits every class, without changing the machine. For
+// an incompletely specified machine it writes the implication table and the
+// closed cover of the reduction instead, see ExplainReduce.
+func (m *MealyMachineInfo) Explain(w io.Writer, format explain.Format) error {
+	return m.ExplainReduce(w, format, automata.DefaultSearchLimit)
+}
+
+// ExplainReduce is Explain with the search for the smallest cover bounded by
+// limit, as in Reduce.
+func (m *MealyMachineInfo) ExplainReduce(w io.Writer, format explain.Format, limit int) error {
+	machine, err := m.ToMealy()
+	if err != nil {
+		return err
+	}
+	if !machine.IsSpecified() {
+		return explain.Write(w, explain.Reduce(machine, limit), format)
+	}
+
+	return explain.Write(w, explain.Mealy(machine), format)
 }
 
 func (m *MealyMachineInfo) deleteUnreachableStates() {
@@ -224,4 +299,38 @@ func (m *MealyMachineInfo) getMinimizedTransitionFunctions(
 	}
 
 	return minimizedTransitionFunctions
-}
\ No newline at end of file
+}
+
+func NewMealyMachineInfoFromMealy(machine *automata.Mealy) *MealyMachineInfo {
+	m := &MealyMachineInfo{
+		States:              make([]string, 0),
+		InnerStates:         append([]string{}, machine.Alphabet...),
+		TransitionFunctions: make([][]string, len(machine.Alphabet)),
+	}
+
+	for _, state := range automata.StartFirst(len(machine.States), machine.Start) {
+		m.States = append(m.States, machine.States[state])
+		for symbol, transition := range machine.Transitions[state] {
+			transitionFunction := ""
+			if transition.Target != automata.NoState {
+				transitionFunction = machine.States[transition.Target] + "/" + transition.Output
+			} else if transition.HasOutput() {
+				transitionFunction = "-/" + transition.Output
+			}
+			m.TransitionFunctions[symbol] = append(m.TransitionFunctions[symbol], transitionFunction)
+		}
+	}
+
+	return m
+}
+
+// ToMealy reads the machine with table.ParseMealy. The records are built here
+// rather than by GetRecords, which sorts the states.
+func (m *MealyMachineInfo) ToMealy() (*automata.Mealy, error) {
+	records := [][]string{append([]string{""}, m.States...)}
+	for i, innerState := range m.InnerStates {
+		records = append(records, append([]string{innerState}, m.TransitionFunctions[i]...))
+	}
+
+	return table.ParseMealy(records)
+}
@@ -2,9 +2,13 @@ package moore
 
 import (
 	"errors"
+	"io"
 	"slices"
 	"strconv"
-	"strings"
+
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
 )
 
 type MooreMachineInfo struct {
@@ -14,7 +18,7 @@ type MooreMachineInfo struct {
     InputAlphabet       []string
 }
 
-func NewMooreMachineInfo(info []string) (*MooreMachineInfo, error) {
+func NewMooreMachineInfo(records [][]string) (*MooreMachineInfo, error) {
     m := &MooreMachineInfo{
         OutputAlphabet:      []string{},
         States:              []string{},
@@ -22,18 +26,18 @@ func NewMooreMachineInfo(info []string) (*MooreMachineInfo, error) {
         TransitionFunctions: [][]string{},
     }
 
-    if info == nil || len(info) == 0 {
+    if len(records) == 0 {
         return m, nil
     }
+    if len(records) == 1 {
+        return nil, errors.New("Incorrect input machine. Row of states is missing")
+    }
 
-    dirtyOutputAlphabet := strings.Split(info[0], ";")
-    m.OutputAlphabet = dirtyOutputAlphabet[1:]
-    dirtyStates := strings.Split(info[1], ";")
-    m.States = dirtyStates[1:]
-    for i := 2; i < len(info); i++ {
-        values := strings.Split(info[i], ";")
-        m.InputAlphabet = append(m.InputAlphabet, values[0])
-        m.TransitionFunctions = append(m.TransitionFunctions, values[1:])
+    m.OutputAlphabet = records[0][1:]
+    m.States = records[1][1:]
+    for i := 2; i < len(records); i++ {
+        m.InputAlphabet = append(m.InputAlphabet, records[i][0])
+        m.TransitionFunctions = append(m.TransitionFunctions, records[i][1:])
     }
 
     if len(m.States) <= 1 {
@@ -43,25 +47,23 @@ func NewMooreMachineInfo(info []string) (*MooreMachineInfo, error) {
     return m, nil
 }
 
-func (m *MooreMachineInfo) GetCsvData() string {
-    csvData := ";"
-    csvData += strings.Join(m.OutputAlphabet, ";")
-    csvData += "\n"
-
-    csvData += ";"
-    csvData += strings.Join(m.States, ";")
-    csvData += "\n"
+func (m *MooreMachineInfo) GetRecords() [][]string {
+    records := make([][]string, 0, len(m.InputAlphabet)+2)
+    records = append(records, append([]string{""}, m.OutputAlphabet...))
+    records = append(records, append([]string{""}, m.States...))
 
     for i := 0; i < len(m.InputAlphabet); i++ {
-        csvData += m.InputAlphabet[i] + ";"
-        csvData += strings.Join(m.TransitionFunctions[i], ";")
-        csvData += "\n"
+        records = append(records, append([]string{m.InputAlphabet[i]}, m.TransitionFunctions[i]...))
     }
 
-    return csvData
+    return records
+}
+
+func (m *MooreMachineInfo) GetCsvData() (string, error) {
+    return table.Format(m.GetRecords(), table.Semicolon)
 }
 
-func (m *MooreMachineInfo) Minimize() {
+func (m *MooreMachineInfo) Minimize() error {
     m.deleteUnreachableStates()
     previousMatchingMinimizedStatesToStates := make(map[string]map[string]struct{})
     matchingMinimizedStatesToStates := make(map[string]map[string]struct{})
@@ -91,6 +93,39 @@ func (m *MooreMachineInfo) Minimize() {
     m.States = minimizedStates
     m.TransitionFunctions = minimizedTransitionFunctions
     m.OutputAlphabet = minimizedOutputAlphabet
+
+    return nil
+}
+
+func (m *MooreMachineInfo) MinimizeWith(algorithm automata.Algorithm) error {
+    machine, err := m.ToMoore()
+    if err != nil {
+        return err
+    }
+
+    if algorithm == automata.Brzozowski {
+        if !machine.IsAcceptor(table.FinalOutput) {
+            return errors.New("brzozowski minimization needs a machine with the outputs F and empty only")
+        }
+        dfa := automata.MinimizeDFAWith(machine.ToDFA(table.FinalOutput), algorithm)
+        *m = *NewMooreMachineInfoFromMoore(dfa.ToMoore(table.FinalOutput, ""))
+        return nil
+    }
+
+    *m = *NewMooreMachineInfoFromMoore(automata.MinimizeMooreWith(machine, algorithm))
+
+    return nil
+}
+
+// Explain writes the k-equivalence partitions that MinimizeWith goes through,
+// with the input that splits every class, without changing the machine.
+func (m *MooreMachineInfo) Explain(w io.Writer, format explain.Format) error {
+    machine, err := m.ToMoore()
+    if err != nil {
+        return err
+    }
+
+    return explain.Write(w, explain.Moore(machine), format)
 }
 
 func toSet(slice []string) map[string]struct{} {
@@ -235,4 +270,31 @@ func (m *MooreMachineInfo) getMinimizedOutputAlphabet(matchingMinimizedStatesToS
     }
 
     return minimizedOutputAlphabet
-}
\ No newline at end of file
+}
+
+func NewMooreMachineInfoFromMoore(machine *automata.Moore) *MooreMachineInfo {
+    m := &MooreMachineInfo{
+        OutputAlphabet:      []string{},
+        States:              []string{},
+        InputAlphabet:       append([]string{}, machine.Alphabet...),
+        TransitionFunctions: make([][]string, len(machine.Alphabet)),
+    }
+
+    for _, state := range automata.StartFirst(len(machine.States), machine.Start) {
+        m.States = append(m.States, machine.States[state])
+        m.OutputAlphabet = append(m.OutputAlphabet, machine.Outputs[state])
+        for symbol := range machine.Alphabet {
+            transitionFunction := ""
+            if target := machine.Transitions[state][symbol]; target != automata.NoState {
+                transitionFunction = machine.States[target]
+            }
+            m.TransitionFunctions[symbol] = append(m.TransitionFunctions[symbol], transitionFunction)
+        }
+    }
+
+    return m
+}
+
+func (m *MooreMachineInfo) ToMoore() (*automata.Moore, error) {
+    return table.ParseMoore(m.GetRecords())
+}
@@ -2,4 +2,8 @@ module mooreMealyConversion
 
 go 1.21.1
 
-require github.com/mzohreva/GoGraphviz v0.0.0-20180226085351-533f4a37d9c6
+require github.com/AkshachRd/automata-theory-2023/automata v0.0.0
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
+replace github.com/AkshachRd/automata-theory-2023/automata => ../automata
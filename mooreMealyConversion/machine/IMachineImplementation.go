@@ -2,13 +2,18 @@ package machine
 
 import (
 	"bufio"
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"io"
 	"mooreMealyConversion/graph"
 	"os"
 )
 
 type IMachineImplementation interface {
-	Draw(graph graph.IGraph)
+	Draw(graph graph.IGraph) error
 	ReadFromFile(scanner *bufio.Scanner, statesNum, inputSymbolsNum uint64) error
 	Print(file *os.File) error
 	Minimize() error
+	MinimizeWith(algorithm automata.Algorithm) error
+	Explain(w io.Writer, format explain.Format) error
 }
@@ -2,10 +2,16 @@ package machine
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
+	"github.com/AkshachRd/automata-theory-2023/automata/diag"
+	"github.com/AkshachRd/automata-theory-2023/automata/document"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"io"
 	"mooreMealyConversion/graph"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -38,6 +44,22 @@ func ReadMachineFromFile(filePath string) (*Machine, error) {
 	}
 	defer file.Close()
 
+	// Check the whole file first, so every problem is reported with its line
+	// and column rather than only the first one.
+	_, err = document.FromHeaderText(file)
+	var diagnostics diag.List
+	if errors.As(err, &diagnostics) {
+		diagnostics.SetFile(filePath)
+		diagnostics.Sort()
+		return nil, diagnostics
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
 	scanner := bufio.NewScanner(file)
 	scanner.Scan()
 	line := scanner.Text()
@@ -88,10 +110,39 @@ func ReadMachineFromFile(filePath string) (*Machine, error) {
 	return nil, errors.New("error unknown type of machine")
 }
 
-func (m *Machine) DrawGraph(outputFileName string) {
+func (m *Machine) DrawGraph(outputFileName string) error {
+	graphView := graph.NewGraph()
+	if err := m.Implementation.Draw(graphView); err != nil {
+		return err
+	}
+	return graphView.GenerateImage(outputFileName)
+}
+
+// ExportGraph writes the machine graph to outputFileName in one of the text
+// formats of graph.IGraph.Export, e.g. mermaid for Markdown docs.
+func (m *Machine) ExportGraph(outputFileName, format string) error {
 	graphView := graph.NewGraph()
-	m.Implementation.Draw(graphView)
-	graphView.GenerateImage(outputFileName)
+	if err := m.Implementation.Draw(graphView); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := graphView.Export(&buf, format); err != nil {
+		return err
+	}
+	return os.WriteFile(outputFileName, buf.Bytes(), 0644)
+}
+
+// ExplainMinimization writes the steps of Minimize to outputFileName, as HTML
+// for .html files and Markdown otherwise.
+func (m *Machine) ExplainMinimization(outputFileName string) error {
+	file, err := os.Create(outputFileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.Implementation.Explain(file, explain.FormatOf(outputFileName))
 }
 
 func (m *Machine) ConvertToMachine(machineType MachineType) error {
@@ -132,3 +183,31 @@ func (m *Machine) Print(outputFileName string) error {
 
 	return nil
 }
+
+func sortedInputSymbols[T any](transitions Transitions[T]) []Symbol {
+	var inputSymbols []Symbol
+	for inputSymbol := range transitions {
+		inputSymbols = append(inputSymbols, inputSymbol)
+	}
+	sort.Slice(inputSymbols, func(i, j int) bool {
+		var n, q int
+		fmt.Sscanf(string(inputSymbols[i]), "x%d", &n)
+		fmt.Sscanf(string(inputSymbols[j]), "x%d", &q)
+
+		if n == q {
+			return inputSymbols[i] < inputSymbols[j]
+		}
+		return n < q
+	})
+
+	return inputSymbols
+}
+
+func symbolsToStrings(symbols []Symbol) []string {
+	strs := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		strs[i] = string(symbol)
+	}
+
+	return strs
+}
@@ -3,6 +3,9 @@ package machine
 import (
 	"bufio"
 	"fmt"
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"io"
 	"mooreMealyConversion/graph"
 	"os"
 	"reflect"
@@ -60,6 +63,9 @@ func (m *MealyMachine) ReadFromFile(scanner *bufio.Scanner, statesNum, inputSymb
 
 			state := MealyState{Name: fmt.Sprintf("s%d", j)}
 			m.States[state] = true
+			if j == 0 {
+				m.CurrentState = state
+			}
 
 			m.Transitions[inputSymbol][state] = MealyTransitionOutput{
 				State: MealyState{Name: transitionOutput[0]}, OutputSymbol: Symbol(transitionOutput[1]),
@@ -70,11 +76,14 @@ func (m *MealyMachine) ReadFromFile(scanner *bufio.Scanner, statesNum, inputSymb
 	return nil
 }
 
-func (m *MealyMachine) Draw(graph graph.IGraph) {
+func (m *MealyMachine) Draw(graph graph.IGraph) error {
 	nodes := make(map[MealyState]int)
 	for state := range m.States {
 		nodes[state] = graph.AddNode(state.Name)
 	}
+	if start, ok := nodes[m.CurrentState]; ok {
+		graph.MarkStart(start)
+	}
 
 	type Edge struct {
 		First  int
@@ -86,7 +95,10 @@ func (m *MealyMachine) Draw(graph graph.IGraph) {
 	for inputSymbol, transition := range m.Transitions {
 		for state, transitionOutput := range transition {
 			first := nodes[state]
-			second := nodes[MealyState{Name: transitionOutput.State.Name}]
+			second, ok := nodes[MealyState{Name: transitionOutput.State.Name}]
+			if !ok {
+				return fmt.Errorf("unknown state %s in transition from %s by %s", transitionOutput.State.Name, state.Name, inputSymbol)
+			}
 
 			edge := Edge{First: first, Second: second}
 			label := string(inputSymbol) + "/" + string(transitionOutput.OutputSymbol)
@@ -102,6 +114,8 @@ func (m *MealyMachine) Draw(graph graph.IGraph) {
 	for edge, label := range edges {
 		graph.AddEdge(edge.First, edge.Second, label)
 	}
+
+	return nil
 }
 
 func (m *MealyMachine) Print(file *os.File) error {
@@ -130,6 +144,34 @@ func (m *MealyMachine) Print(file *os.File) error {
 	return nil
 }
 
+// Step feeds one input symbol and moves CurrentState to the next state.
+func (m *MealyMachine) Step(inputSymbol Symbol) (Symbol, error) {
+	transition, ok := m.Transitions[inputSymbol]
+	if !ok {
+		return "", fmt.Errorf("unknown input symbol %s", inputSymbol)
+	}
+	transitionOutput, ok := transition[m.CurrentState]
+	if !ok {
+		return "", fmt.Errorf("state %s has no transition by %s", m.CurrentState.Name, inputSymbol)
+	}
+	if !m.States[transitionOutput.State] {
+		return "", fmt.Errorf("unknown state %s in transition from %s by %s", transitionOutput.State.Name, m.CurrentState.Name, inputSymbol)
+	}
+
+	m.CurrentState = transitionOutput.State
+	return transitionOutput.OutputSymbol, nil
+}
+
+// Run feeds the whole word starting from CurrentState without moving it.
+func (m *MealyMachine) Run(word []Symbol) (*automata.Trace, error) {
+	machine, err := m.ToMealy()
+	if err != nil {
+		return nil, err
+	}
+
+	return machine.Simulate(symbolsToStrings(word))
+}
+
 type MealyPartition []MealyState
 
 func (m *MealyMachine) Minimize() error {
@@ -150,6 +192,32 @@ func (m *MealyMachine) Minimize() error {
 	return nil
 }
 
+func (m *MealyMachine) MinimizeWith(algorithm automata.Algorithm) error {
+	if algorithm == automata.Brzozowski {
+		return fmt.Errorf("brzozowski minimization works on acceptors only, not on mealy machines")
+	}
+
+	machine, err := m.ToMealy()
+	if err != nil {
+		return err
+	}
+
+	*m = *NewMealyMachineFromMealy(automata.MinimizeMealyWith(machine, algorithm))
+
+	return nil
+}
+
+// Explain writes the k-equivalence partitions that Minimize refines, the
+// MealyPartitions of every step, with the input that splits every class.
+func (m *MealyMachine) Explain(w io.Writer, format explain.Format) error {
+	machine, err := m.ToMealy()
+	if err != nil {
+		return err
+	}
+
+	return explain.Write(w, explain.Mealy(machine), format)
+}
+
 func (m *MealyMachine) partitionsToMachine(partitions []MealyPartition) {
 	newStates := make(map[MealyState]bool)
 	newTransitions := make(Transitions[MealyTransition])
@@ -196,7 +264,7 @@ func (m *MealyMachine) partitionsToMachine(partitions []MealyPartition) {
 
 	m.States = newStates
 	m.Transitions = newTransitions
-	m.CurrentState = MealyState{Name: "q0"}
+	m.CurrentState = statesToNewStates[m.CurrentState]
 }
 
 func (m *MealyMachine) getInitialPartitions() []MealyPartition {
@@ -280,3 +348,80 @@ func (m *MealyMachine) checkIfInSamePartition(partitions []MealyPartition, first
 
 	return inSamePartition
 }
+
+func NewMealyMachineFromMealy(machine *automata.Mealy) *MealyMachine {
+	m := &MealyMachine{
+		InputSymbolsNum: uint64(len(machine.Alphabet)),
+		States:          make(map[MealyState]bool, len(machine.States)),
+		Transitions:     make(Transitions[MealyTransition], len(machine.Alphabet)),
+		CurrentState:    MealyState{Name: machine.States[machine.Start]},
+	}
+
+	for _, name := range machine.States {
+		m.States[MealyState{Name: name}] = true
+	}
+
+	for symbol, inputSymbol := range machine.Alphabet {
+		transition := make(MealyTransition, len(machine.States))
+		for i, name := range machine.States {
+			mealyTransition := machine.Transitions[i][symbol]
+			if mealyTransition.Target == automata.NoState {
+				continue
+			}
+			transition[MealyState{Name: name}] = MealyTransitionOutput{
+				State:        MealyState{Name: machine.States[mealyTransition.Target]},
+				OutputSymbol: Symbol(mealyTransition.Output),
+			}
+		}
+		m.Transitions[Symbol(inputSymbol)] = transition
+	}
+
+	return m
+}
+
+func (m *MealyMachine) ToMealy() (*automata.Mealy, error) {
+	var states []MealyState
+	for state := range m.States {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		var n, q int
+		fmt.Sscanf(states[i].Name, "s%d", &n)
+		fmt.Sscanf(states[j].Name, "s%d", &q)
+
+		if n == q {
+			return states[i].Name < states[j].Name
+		}
+		return n < q
+	})
+	inputSymbols := sortedInputSymbols(m.Transitions)
+
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = state.Name
+	}
+	alphabet := make(automata.Alphabet, len(inputSymbols))
+	for i, inputSymbol := range inputSymbols {
+		alphabet[i] = string(inputSymbol)
+	}
+
+	machine := automata.NewMealy(names, alphabet)
+	for i, state := range states {
+		if state == m.CurrentState {
+			machine.Start = i
+		}
+		for symbol, inputSymbol := range inputSymbols {
+			transitionOutput, ok := m.Transitions[inputSymbol][state]
+			if !ok {
+				continue
+			}
+			target := machine.StateIndex(transitionOutput.State.Name)
+			if target == -1 {
+				return nil, fmt.Errorf("unknown state %s in transition from %s by %s", transitionOutput.State.Name, state.Name, inputSymbol)
+			}
+			machine.Transitions[i][symbol] = automata.MealyTransition{Target: target, Output: string(transitionOutput.OutputSymbol)}
+		}
+	}
+
+	return machine, machine.Validate()
+}
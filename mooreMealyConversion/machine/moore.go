@@ -3,6 +3,10 @@ package machine
 import (
 	"bufio"
 	"fmt"
+	"github.com/AkshachRd/automata-theory-2023/automata"
+	"github.com/AkshachRd/automata-theory-2023/automata/explain"
+	"github.com/AkshachRd/automata-theory-2023/automata/table"
+	"io"
 	"mooreMealyConversion/graph"
 	"os"
 	"reflect"
@@ -67,6 +71,9 @@ func (m *MooreMachine) ReadFromFile(scanner *bufio.Scanner, statesNum, inputSymb
 		for j, transitionOutputString := range transitionOutputStrings {
 			state := MooreState{OutputSymbol: outputSymbols[j], Name: fmt.Sprintf("s%d", j)}
 			m.States[state] = true
+			if j == 0 {
+				m.CurrentState = state
+			}
 
 			m.Transitions[inputSymbol][state] = transitionOutputString
 		}
@@ -75,10 +82,13 @@ func (m *MooreMachine) ReadFromFile(scanner *bufio.Scanner, statesNum, inputSymb
 	return nil
 }
 
-func (m *MooreMachine) Draw(graph graph.IGraph) {
+func (m *MooreMachine) Draw(graph graph.IGraph) error {
 	nodes := make(map[MooreState]int)
 	for state := range m.States {
-		nodes[state] = graph.AddNode(state.Name + "/" + string(state.OutputSymbol))
+		nodes[state] = graph.AddOutputNode(state.Name, string(state.OutputSymbol))
+	}
+	if start, ok := nodes[m.CurrentState]; ok {
+		graph.MarkStart(start)
 	}
 
 	type Edge struct {
@@ -101,7 +111,7 @@ func (m *MooreMachine) Draw(graph graph.IGraph) {
 				}
 			}
 			if !found {
-				panic("invalid state")
+				return fmt.Errorf("unknown state %s in transition from %s by %s", transitionOutput, state.Name, inputSymbol)
 			}
 
 			to := nodes[MooreState{Name: transitionOutput, OutputSymbol: toNodeSymbol}]
@@ -120,22 +130,14 @@ func (m *MooreMachine) Draw(graph graph.IGraph) {
 	for edge, label := range edges {
 		graph.AddEdge(edge.From, edge.To, label)
 	}
+
+	return nil
 }
 
 func (m *MooreMachine) Print(file *os.File) error {
 	writer := bufio.NewWriter(file)
 
-	var sortedStates []MooreState
-	for state := range m.States {
-		sortedStates = append(sortedStates, state)
-	}
-	sort.Slice(sortedStates, func(i, j int) bool {
-		var n, q int
-		fmt.Sscanf(sortedStates[i].Name, "s%d", &n)
-		fmt.Sscanf(sortedStates[j].Name, "s%d", &q)
-
-		return n < q
-	})
+	sortedStates := m.sortedStates()
 
 	for _, state := range sortedStates {
 		fmt.Fprint(writer, state.OutputSymbol, " ")
@@ -169,6 +171,35 @@ func (m *MooreMachine) Print(file *os.File) error {
 	return nil
 }
 
+// Step feeds one input symbol and moves CurrentState to the next state.
+func (m *MooreMachine) Step(inputSymbol Symbol) (Symbol, error) {
+	transition, ok := m.Transitions[inputSymbol]
+	if !ok {
+		return "", fmt.Errorf("unknown input symbol %s", inputSymbol)
+	}
+	nextName, ok := transition[m.CurrentState]
+	if !ok {
+		return "", fmt.Errorf("state %s has no transition by %s", m.CurrentState.Name, inputSymbol)
+	}
+	next := m.findStateByName(nextName)
+	if next.Name == "" {
+		return "", fmt.Errorf("unknown state %s in transition from %s by %s", nextName, m.CurrentState.Name, inputSymbol)
+	}
+
+	m.CurrentState = next
+	return next.OutputSymbol, nil
+}
+
+// Run feeds the whole word starting from CurrentState without moving it.
+func (m *MooreMachine) Run(word []Symbol) (*automata.Trace, error) {
+	machine, err := m.ToMoore()
+	if err != nil {
+		return nil, err
+	}
+
+	return machine.Simulate(symbolsToStrings(word))
+}
+
 type MoorePartition []string
 
 func (m *MooreMachine) Minimize() error {
@@ -189,6 +220,37 @@ func (m *MooreMachine) Minimize() error {
 	return nil
 }
 
+func (m *MooreMachine) MinimizeWith(algorithm automata.Algorithm) error {
+	machine, err := m.ToMoore()
+	if err != nil {
+		return err
+	}
+
+	if algorithm == automata.Brzozowski {
+		if !machine.IsAcceptor(table.FinalOutput) {
+			return fmt.Errorf("brzozowski minimization needs a machine with the outputs F and empty only")
+		}
+		dfa := automata.MinimizeDFAWith(machine.ToDFA(table.FinalOutput), algorithm)
+		*m = *NewMooreMachineFromMoore(dfa.ToMoore(table.FinalOutput, ""))
+		return nil
+	}
+
+	*m = *NewMooreMachineFromMoore(automata.MinimizeMooreWith(machine, algorithm))
+
+	return nil
+}
+
+// Explain writes the k-equivalence partitions that Minimize refines, the
+// MoorePartitions of every step, with the input that splits every class.
+func (m *MooreMachine) Explain(w io.Writer, format explain.Format) error {
+	machine, err := m.ToMoore()
+	if err != nil {
+		return err
+	}
+
+	return explain.Write(w, explain.Moore(machine), format)
+}
+
 func (m *MooreMachine) partitionsToMachine(partitions []MoorePartition) {
 	newStates := make(map[MooreState]bool)
 	newTransitions := make(Transitions[MooreTransition])
@@ -218,7 +280,7 @@ func (m *MooreMachine) partitionsToMachine(partitions []MoorePartition) {
 
 	m.States = newStates
 	m.Transitions = newTransitions
-	m.CurrentState = MooreState{Name: "q0", OutputSymbol: m.CurrentState.OutputSymbol}
+	m.CurrentState = oldStatesToNewStates[m.CurrentState]
 }
 
 func (m *MooreMachine) getInitialPartitions() []MoorePartition {
@@ -310,4 +372,85 @@ func (m *MooreMachine) findStateByName(name string) MooreState {
 	}
 
 	return MooreState{}
-}
\ No newline at end of file
+}
+
+func NewMooreMachineFromMoore(machine *automata.Moore) *MooreMachine {
+	m := &MooreMachine{
+		InputSymbolsNum: uint64(len(machine.Alphabet)),
+		States:          make(map[MooreState]bool, len(machine.States)),
+		Transitions:     make(Transitions[MooreTransition], len(machine.Alphabet)),
+	}
+
+	states := make([]MooreState, len(machine.States))
+	for i, name := range machine.States {
+		states[i] = MooreState{Name: name, OutputSymbol: Symbol(machine.Outputs[i])}
+		m.States[states[i]] = true
+	}
+	m.CurrentState = states[machine.Start]
+
+	for symbol, inputSymbol := range machine.Alphabet {
+		transition := make(MooreTransition, len(machine.States))
+		for i, state := range states {
+			if target := machine.Transitions[i][symbol]; target != automata.NoState {
+				transition[state] = machine.States[target]
+			}
+		}
+		m.Transitions[Symbol(inputSymbol)] = transition
+	}
+
+	return m
+}
+
+func (m *MooreMachine) ToMoore() (*automata.Moore, error) {
+	states := m.sortedStates()
+	inputSymbols := sortedInputSymbols(m.Transitions)
+
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = state.Name
+	}
+	alphabet := make(automata.Alphabet, len(inputSymbols))
+	for i, inputSymbol := range inputSymbols {
+		alphabet[i] = string(inputSymbol)
+	}
+
+	machine := automata.NewMoore(names, alphabet)
+	for i, state := range states {
+		machine.Outputs[i] = string(state.OutputSymbol)
+		if state.Name == m.CurrentState.Name {
+			machine.Start = i
+		}
+		for symbol, inputSymbol := range inputSymbols {
+			targetName, ok := m.Transitions[inputSymbol][state]
+			if !ok {
+				continue
+			}
+			target := machine.StateIndex(targetName)
+			if target == -1 {
+				return nil, fmt.Errorf("unknown state %s in transition from %s by %s", targetName, state.Name, inputSymbol)
+			}
+			machine.Transitions[i][symbol] = target
+		}
+	}
+
+	return machine, machine.Validate()
+}
+
+func (m *MooreMachine) sortedStates() []MooreState {
+	var sortedStates []MooreState
+	for state := range m.States {
+		sortedStates = append(sortedStates, state)
+	}
+	sort.Slice(sortedStates, func(i, j int) bool {
+		var n, q int
+		fmt.Sscanf(sortedStates[i].Name, "s%d", &n)
+		fmt.Sscanf(sortedStates[j].Name, "s%d", &q)
+
+		if n == q {
+			return sortedStates[i].Name < sortedStates[j].Name
+		}
+		return n < q
+	})
+
+	return sortedStates
+}
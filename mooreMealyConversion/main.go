@@ -2,11 +2,60 @@ package main
 
 import (
 	"fmt"
+	"github.com/AkshachRd/automata-theory-2023/automata"
 	"mooreMealyConversion/machine"
+	"os"
+	"strings"
 )
 
+const (
+	EXPLAIN_ARG_PREFIX = "--explain="
+	FORMAT_ARG_PREFIX  = "--format="
+
+	DEFAULT_INPUT_FILE_PATH = "./moore-in-5.txt"
+)
+
+// inputFilePath returns the first argument that is not a --flag, the machine
+// to read, or DEFAULT_INPUT_FILE_PATH when there is none.
+func inputFilePath(args []string) string {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			return arg
+		}
+	}
+
+	return DEFAULT_INPUT_FILE_PATH
+}
+
+// argValue returns the value of the prefix=<value> argument among args, or ""
+// when the argument is missing. --explain=<file> names the file where the
+// steps of the minimization are written as HTML for .html and Markdown
+// otherwise, --format=<format> exports the graphs as mermaid, plantuml or dot
+// text instead of drawing them.
+func argValue(args []string, prefix string) string {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			return strings.TrimPrefix(arg, prefix)
+		}
+	}
+
+	return ""
+}
+
+// drawMachine draws the graph to outputFileName, or exports it to
+// outputFileName.<format> when a format is given.
+func drawMachine(m *machine.Machine, outputFileName, format string) error {
+	if format == "" {
+		return m.DrawGraph(outputFileName)
+	}
+
+	return m.ExportGraph(outputFileName+"."+format, format)
+}
+
 func main() {
-	myMachine, err := machine.ReadMachineFromFile("./moore-in-5.txt")
+	format := argValue(os.Args[1:], FORMAT_ARG_PREFIX)
+
+	myMachine, err := machine.ReadMachineFromFile(inputFilePath(os.Args[1:]))
 	if err != nil {
 		fmt.Println("error reading a machine from file", err)
 		return
@@ -17,9 +66,21 @@ func main() {
 		fmt.Println("error printing the machine", err)
 		return
 	}
-	myMachine.DrawGraph("original-machine")
+	err = drawMachine(myMachine, "original-machine", format)
+	if err != nil {
+		fmt.Println("error drawing the machine", err)
+		return
+	}
+
+	if filePath := argValue(os.Args[1:], EXPLAIN_ARG_PREFIX); filePath != "" {
+		err = myMachine.ExplainMinimization(filePath)
+		if err != nil {
+			fmt.Println("error explaining the minimization", err)
+			return
+		}
+	}
 
-	err = myMachine.Implementation.Minimize()
+	err = myMachine.Implementation.MinimizeWith(automata.Refinement)
 	if err != nil {
 		fmt.Println("error minimizing the machine", err)
 		return
@@ -30,7 +91,11 @@ func main() {
 		fmt.Println("error printing the machine", err)
 		return
 	}
-	myMachine.DrawGraph("converted-machine")
+	err = drawMachine(myMachine, "converted-machine", format)
+	if err != nil {
+		fmt.Println("error drawing the machine", err)
+		return
+	}
 
 	// if myMachine.Type == machine.Mealy {
 	// 	err = myMachine.ConvertToMachine(machine.Moore)